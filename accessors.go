@@ -0,0 +1,92 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var ErrTypeMismatch = errors.New("type mismatch")
+
+// Get returns the value of the field of the target at the dotted path, e.g.
+// "Database.Port". Nil pointers on the way result in the zero value of the field.
+func (pr *Primordius) Get(path string) (any, error) {
+	f, err := pr.field(path)
+	if err != nil {
+		return nil, err
+	}
+	return f.Interface(), nil
+}
+
+// GetString returns the value of the string field at the dotted path.
+func (pr *Primordius) GetString(path string) (string, error) {
+	f, err := pr.fieldOfKind(path, "string", reflect.String)
+	if err != nil {
+		return "", err
+	}
+	return f.String(), nil
+}
+
+// GetInt returns the value of the signed integer field at the dotted path.
+// time.Duration fields are not considered integers; use GetDuration.
+func (pr *Primordius) GetInt(path string) (int, error) {
+	f, err := pr.fieldOfKind(path, "int", reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64)
+	if err != nil {
+		return 0, err
+	}
+	if f.Type() == durationType {
+		return 0, fmt.Errorf("%w: field %s is a time.Duration, not an int", ErrTypeMismatch, path)
+	}
+	return int(f.Int()), nil
+}
+
+// GetBool returns the value of the bool field at the dotted path.
+func (pr *Primordius) GetBool(path string) (bool, error) {
+	f, err := pr.fieldOfKind(path, "bool", reflect.Bool)
+	if err != nil {
+		return false, err
+	}
+	return f.Bool(), nil
+}
+
+// GetDuration returns the value of the time.Duration field at the dotted path.
+func (pr *Primordius) GetDuration(path string) (time.Duration, error) {
+	f, err := pr.field(path)
+	if err != nil {
+		return 0, err
+	}
+	if f.Type() != durationType {
+		return 0, fmt.Errorf("%w: field %s is a %s, not a time.Duration", ErrTypeMismatch, path, f.Type())
+	}
+	return time.Duration(f.Int()), nil
+}
+
+// field returns a copy of the field of the target at the dotted path.
+func (pr *Primordius) field(path string) (reflect.Value, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	if err := checkTarget(pr.target); err != nil {
+		return reflect.Value{}, err
+	}
+	f, err := fieldByPath(reflect.ValueOf(pr.target).Elem(), path, false)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	return deepCopy(f)
+}
+
+// fieldOfKind returns the field of the target at the dotted path and an error wrapping
+// ErrTypeMismatch if it is not of one of the given kinds, described by name.
+func (pr *Primordius) fieldOfKind(path, name string, kinds ...reflect.Kind) (reflect.Value, error) {
+	f, err := pr.field(path)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	for _, kind := range kinds {
+		if f.Kind() == kind {
+			return f, nil
+		}
+	}
+	return reflect.Value{}, fmt.Errorf("%w: field %s is a %s, not a %s", ErrTypeMismatch, path, f.Type(), name)
+}
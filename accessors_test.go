@@ -0,0 +1,56 @@
+package primordius
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPrimordius_typedAccessors(t *testing.T) {
+	type database struct {
+		Host    string
+		Port    int16
+		Timeout time.Duration
+	}
+	target := struct {
+		Debug    bool
+		Name     string
+		Database database
+		Replica  *database
+	}{Debug: true, Name: "app", Database: database{Host: "db.local", Port: 5432, Timeout: 3 * time.Second}}
+	pr := New(&target)
+
+	tests := []struct {
+		name    string
+		get     func() (any, error)
+		want    any
+		wantErr error
+	}{
+		{"string", func() (any, error) { return pr.GetString("Database.Host") }, "db.local", nil},
+		{"int", func() (any, error) { return pr.GetInt("Database.Port") }, 5432, nil},
+		{"bool", func() (any, error) { return pr.GetBool("Debug") }, true, nil},
+		{"duration", func() (any, error) { return pr.GetDuration("Database.Timeout") }, 3 * time.Second, nil},
+		{"nil pointer on the way", func() (any, error) { return pr.GetString("Replica.Host") }, "", nil},
+		{"generic", func() (any, error) { return pr.Get("Name") }, "app", nil},
+		{"string mismatch", func() (any, error) { return pr.GetString("Debug") }, "", ErrTypeMismatch},
+		{"duration is no int", func() (any, error) { return pr.GetInt("Database.Timeout") }, 0, ErrTypeMismatch},
+		{"int is no duration", func() (any, error) { return pr.GetDuration("Database.Port") }, time.Duration(0), ErrTypeMismatch},
+		{"struct is no bool", func() (any, error) { return pr.GetBool("Database") }, false, ErrTypeMismatch},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := tc.get()
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("error = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+
+	if _, err := pr.GetString("Database.User"); err == nil {
+		t.Error("GetString() error = nil, want error for unknown field")
+	}
+}
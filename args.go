@@ -0,0 +1,29 @@
+package primordius
+
+import "strings"
+
+type argsSource struct {
+	args []string
+	conv *converter
+}
+
+func (as *argsSource) ToTarget(t any) error {
+	values := make(valueMap, len(as.args))
+	for _, arg := range as.args {
+		key, val, ok := strings.Cut(arg, "=")
+		if !ok || key == "" {
+			continue
+		}
+		values[key] = val
+	}
+
+	return as.conv.applyTagged(t, values)
+}
+
+// FromArgs adds a Source to pr which reads values from KEY=VALUE arguments, e.g.
+// os.Args[1:]. Keys are matched against the 'env' tag and values are converted
+// like those of environment variables. Arguments not in KEY=VALUE form are ignored;
+// if a key occurs multiple times, the last occurrence wins.
+func (pr *Primordius) FromArgs(args []string) {
+	pr.AddSource(&argsSource{args: args, conv: pr.conv})
+}
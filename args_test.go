@@ -0,0 +1,37 @@
+package primordius
+
+import "testing"
+
+func Test_argsSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host    string `env:"HOST"`
+		Port    int    `env:"PORT"`
+		Verbose bool   `env:"VERBOSE"`
+	}
+
+	tests := []struct {
+		name    string
+		args    []string
+		want    target
+		wantErr bool
+	}{
+		{"no args", nil, target{}, false},
+		{"all keys", []string{"HOST=example.com", "PORT=80", "VERBOSE=true"}, target{"example.com", 80, true}, false},
+		{"value containing =", []string{"HOST=a=b"}, target{Host: "a=b"}, false},
+		{"positional args are ignored", []string{"serve", "--fast", "PORT=80"}, target{Port: 80}, false},
+		{"last occurrence wins", []string{"PORT=80", "PORT=81"}, target{Port: 81}, false},
+		{"invalid value", []string{"PORT=eighty"}, target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&argsSource{args: tc.args}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package primordius
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// autoFormats is the order in which formats are tried when detecting the format of
+// content. As YAML is a superset of JSON, the stricter JSON is tried first.
+var autoFormats = []Format{FormatJSON, FormatYAML, FormatTOML}
+
+type autoReaderSource struct {
+	r io.Reader
+}
+
+func (as *autoReaderSource) ToTarget(t any) error {
+	cont, err := io.ReadAll(as.r)
+	if err != nil {
+		return err
+	}
+	format, err := detectFormat(cont, t)
+	if err != nil {
+		return err
+	}
+
+	return decode(format, cont, t)
+}
+
+// detectFormat returns the first of autoFormats which decodes cont into a fresh
+// value of the type t points to without error. t itself is not modified.
+func detectFormat(cont []byte, t any) (Format, error) {
+	tt := reflect.TypeOf(t)
+	if tt == nil || tt.Kind() != reflect.Pointer {
+		return "", ErrInvalidSpecification
+	}
+
+	msgs := make([]string, 0, len(autoFormats))
+	for _, format := range autoFormats {
+		err := decode(format, cont, reflect.New(tt.Elem()).Interface())
+		if err == nil {
+			return format, nil
+		}
+		msgs = append(msgs, fmt.Sprintf("%s: %s", format, err))
+	}
+
+	return "", fmt.Errorf("%w: content could not be decoded (%s)", ErrUnknownFormat, strings.Join(msgs, "; "))
+}
+
+// FromReaderAuto adds a Source to pr which reads content of unknown format from r.
+// The content is buffered and decoded using the first format that decodes it
+// into the target without error, trying JSON, YAML and TOML in this order.
+func (pr *Primordius) FromReaderAuto(r io.Reader) {
+	pr.AddSource(&autoReaderSource{r: r})
+}
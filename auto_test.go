@@ -0,0 +1,42 @@
+package primordius
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func Test_autoReaderSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+		Port int    `json:"port" yaml:"port" toml:"port"`
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		want    target
+		wantErr bool
+	}{
+		{"JSON", `{"host": "example.com", "port": 80}`, target{"example.com", 80}, false},
+		{"YAML", "host: example.com\nport: 80", target{"example.com", 80}, false},
+		{"TOML", "host = \"example.com\"\nport = 80", target{"example.com", 80}, false},
+		{"garbage", "{host: [", target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := (&autoReaderSource{r: strings.NewReader(tc.content)}).ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr && !errors.Is(err, ErrUnknownFormat) {
+				t.Errorf("ToTarget() error = %v, want %v", err, ErrUnknownFormat)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
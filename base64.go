@@ -0,0 +1,41 @@
+package primordius
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+)
+
+type base64EnvSource struct {
+	key    string
+	format Format
+}
+
+func (bs *base64EnvSource) ToTarget(t any) error {
+	return decodeSource(bs, t)
+}
+
+func (bs *base64EnvSource) describe() string {
+	return bs.key
+}
+
+func (bs *base64EnvSource) load() ([]byte, Format, error) {
+	val, exists := os.LookupEnv(bs.key)
+	if !exists {
+		return nil, bs.format, nil
+	}
+	cont, err := base64.StdEncoding.DecodeString(strings.TrimSpace(val))
+	if err != nil {
+		return nil, bs.format, fmt.Errorf("env var %s: %w", bs.key, err)
+	}
+
+	return cont, bs.format, nil
+}
+
+// FromBase64Env adds a Source to pr which reads a whole configuration file, encoded
+// in base64, from the environment variable key and decodes it according to format.
+// If the variable is not set, the source does nothing.
+func (pr *Primordius) FromBase64Env(key string, format Format) {
+	pr.AddSource(&base64EnvSource{key: key, format: format})
+}
@@ -0,0 +1,49 @@
+package primordius
+
+import (
+	"encoding/base64"
+	"os"
+	"testing"
+)
+
+func Test_base64EnvSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	}
+
+	tests := []struct {
+		name    string
+		value   *string
+		want    target
+		wantErr bool
+	}{
+		{"unset", nil, target{}, false},
+		{"valid file", strPtr(base64.StdEncoding.EncodeToString([]byte("host: example.com\nport: 80"))), target{"example.com", 80}, false},
+		{"invalid base64", strPtr("not base64!"), target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			const key = "PRIMORDIUS_TEST_CONFIG_B64"
+			if tc.value != nil {
+				if err := os.Setenv(key, *tc.value); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Unsetenv(key)
+			}
+
+			var got target
+			if err := (&base64EnvSource{key: key, format: FormatYAML}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func strPtr(s string) *string {
+	return &s
+}
@@ -0,0 +1,58 @@
+package primordius
+
+import (
+	"fmt"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"io"
+)
+
+const (
+	FormatCBOR    Format = "cbor"
+	FormatMsgpack Format = "msgpack"
+)
+
+func init() {
+	RegisterCodec(FormatCBOR, func(content []byte, t any) error {
+		if err := cbor.Unmarshal(content, t); err != nil {
+			return fmt.Errorf("decoding CBOR: %w", err)
+		}
+		return nil
+	})
+	RegisterCodec(FormatMsgpack, func(content []byte, t any) error {
+		if err := msgpack.Unmarshal(content, t); err != nil {
+			return fmt.Errorf("decoding msgpack: %w", err)
+		}
+		return nil
+	})
+}
+
+// FromCBORFile adds a Source to pr which reads values from a CBOR file.
+func (pr *Primordius) FromCBORFile(name string) {
+	pr.FromFile(name, FormatCBOR)
+}
+
+// FromCBOR adds a Source to pr which reads values from a CBOR block.
+func (pr *Primordius) FromCBOR(content []byte) {
+	pr.FromContent(content, FormatCBOR)
+}
+
+// FromCBORReader adds a Source to pr which reads CBOR content from r.
+func (pr *Primordius) FromCBORReader(r io.Reader) {
+	pr.FromReader(r, FormatCBOR)
+}
+
+// FromMsgpackFile adds a Source to pr which reads values from a MessagePack file.
+func (pr *Primordius) FromMsgpackFile(name string) {
+	pr.FromFile(name, FormatMsgpack)
+}
+
+// FromMsgpack adds a Source to pr which reads values from a MessagePack block.
+func (pr *Primordius) FromMsgpack(content []byte) {
+	pr.FromContent(content, FormatMsgpack)
+}
+
+// FromMsgpackReader adds a Source to pr which reads MessagePack content from r.
+func (pr *Primordius) FromMsgpackReader(r io.Reader) {
+	pr.FromReader(r, FormatMsgpack)
+}
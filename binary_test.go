@@ -0,0 +1,52 @@
+package primordius
+
+import (
+	"bytes"
+	"github.com/fxamacker/cbor/v2"
+	"github.com/vmihailenco/msgpack/v5"
+	"testing"
+)
+
+func Test_binaryFormats(t *testing.T) {
+	type target struct {
+		Host string `cbor:"host" msgpack:"host"`
+		Port int    `cbor:"port" msgpack:"port"`
+	}
+	want := target{Host: "example.com", Port: 8080}
+
+	cborContent, err := cbor.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgpackContent, err := msgpack.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		add     func(pr *Primordius)
+		wantErr bool
+	}{
+		{"CBOR block", func(pr *Primordius) { pr.FromCBOR(cborContent) }, false},
+		{"CBOR reader", func(pr *Primordius) { pr.FromCBORReader(bytes.NewReader(cborContent)) }, false},
+		{"invalid CBOR", func(pr *Primordius) { pr.FromCBOR([]byte{0xff, 0x00}) }, true},
+		{"msgpack block", func(pr *Primordius) { pr.FromMsgpack(msgpackContent) }, false},
+		{"msgpack reader", func(pr *Primordius) { pr.FromMsgpackReader(bytes.NewReader(msgpackContent)) }, false},
+		{"invalid msgpack", func(pr *Primordius) { pr.FromMsgpack([]byte{0xc1}) }, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			tc.add(pr)
+			if err := pr.Process(); (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != want {
+				t.Errorf("Process() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
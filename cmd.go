@@ -0,0 +1,124 @@
+package primordius
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	cmdTagName = "cmd"
+	// DefaultCommandTimeout is the default time a command of a cmd tag may run.
+	DefaultCommandTimeout = 10 * time.Second
+)
+
+var ErrCommandsDisabled = errors.New("command execution is disabled")
+
+type commandSource struct {
+	conv *converter
+}
+
+// SetCommandExecution enables or disables running the commands of cmd tags by sources
+// added using FromCommands, and sets the time each command may run. A non-positive
+// timeout selects DefaultCommandTimeout. Command execution is disabled by default.
+//
+// Enabling command execution means that whoever controls the struct definition can
+// run arbitrary programs with the privileges of your application. It is intended for
+// development workflows, e.g. to read secrets using a password manager CLI; avoid it
+// in production.
+func (pr *Primordius) SetCommandExecution(enabled bool, timeout time.Duration) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.commandsEnabled = enabled
+	pr.conv.commandTimeout = timeout
+}
+
+func (cs *commandSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	return cs.apply(v.Elem(), "")
+}
+
+// apply runs the commands of the cmd tags of the fields of the struct s at path,
+// descending into nested structs, and assigns the output to the fields.
+func (cs *commandSource) apply(s reflect.Value, path string) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		f := s.Field(i)
+
+		command := strings.TrimSpace(sf.Tag.Get(cmdTagName))
+		if command == "" || command == "-" {
+			if f.Kind() == reflect.Pointer && !f.IsNil() {
+				f = f.Elem()
+			}
+			if hasExportedFields(f.Type()) {
+				if err := cs.apply(f, fieldPath); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if cs.conv == nil || !cs.conv.commandsEnabled {
+			return fmt.Errorf("%w: field %s has a cmd tag", ErrCommandsDisabled, fieldPath)
+		}
+		out, err := cs.run(command)
+		if err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+		if err := setValue(f, out); err != nil {
+			return fmt.Errorf("field %s: %w", fieldPath, err)
+		}
+	}
+
+	return nil
+}
+
+// run executes command, split into arguments at whitespace, and returns its trimmed
+// standard output.
+func (cs *commandSource) run(command string) (string, error) {
+	timeout := cs.conv.commandTimeout
+	if timeout <= 0 {
+		timeout = DefaultCommandTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	args := strings.Fields(command)
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("running %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
+
+// FromCommands adds a Source to pr which sets fields tagged with cmd to the trimmed
+// standard output of the command in the tag, e.g.
+//
+//	Token string `cmd:"op read op://vault/item/token"`
+//
+// The command is split into arguments at whitespace and run directly, without a shell.
+// As running commands is dangerous, the source fails with an error wrapping
+// ErrCommandsDisabled unless command execution was enabled using SetCommandExecution.
+func (pr *Primordius) FromCommands() {
+	pr.AddSource(&commandSource{conv: pr.conv})
+}
@@ -0,0 +1,66 @@
+package primordius
+
+import (
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func Test_commandSource_ToTarget(t *testing.T) {
+	for _, name := range []string{"echo", "sleep", "false"} {
+		if _, err := exec.LookPath(name); err != nil {
+			t.Skipf("%s executable not available", name)
+		}
+	}
+
+	type nested struct {
+		Port int `cmd:"echo  8080 "`
+	}
+	type target struct {
+		Token  string `cmd:"echo secret-token"`
+		Nested nested
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		target  any
+		wantErr error
+	}{
+		{"disabled", false, &target{}, ErrCommandsDisabled},
+		{"enabled", true, &target{}, nil},
+		{"failing command", true, &struct {
+			Token string `cmd:"false"`
+		}{}, errAny},
+		{"timeout", true, &struct {
+			Token string `cmd:"sleep 5"`
+		}{}, errAny},
+		{"invalid output", true, &struct {
+			Port int `cmd:"echo high"`
+		}{}, errAny},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			cs := &commandSource{conv: &converter{commandsEnabled: tc.enabled, commandTimeout: 200 * time.Millisecond}}
+			err := cs.ToTarget(tc.target)
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("ToTarget() error = %v", err)
+			case tc.wantErr == errAny && err == nil:
+				t.Fatal("ToTarget() error = nil, want error")
+			case tc.wantErr != nil && tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+				t.Fatalf("ToTarget() error = %v, want %v", err, tc.wantErr)
+			}
+			if got, ok := tc.target.(*target); ok && err == nil {
+				if got.Token != "secret-token" || got.Nested.Port != 8080 {
+					t.Errorf("ToTarget() got = %+v", got)
+				}
+			}
+		})
+	}
+}
+
+// errAny marks test cases expecting an arbitrary error.
+var errAny = errors.New("any error")
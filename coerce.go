@@ -0,0 +1,193 @@
+package primordius
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// coerceContent decodes content into a generic map, reconciles scalar values whose
+// type doesn't match the respective field of the struct t according to coerceScalar
+// and encodes the result in format again. Content in formats without an encoder is
+// returned unchanged.
+func coerceContent(format Format, content []byte, t reflect.Type) ([]byte, error) {
+	encode, ok := encoders[format]
+	if !ok {
+		return content, nil
+	}
+	fn, ok := codec(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	raw, err := decodeMap(format, fn, stripBOM(content))
+	if err != nil {
+		return nil, err
+	}
+	coerceMap(raw, t, format)
+
+	return encode(raw)
+}
+
+// coerceMap coerces the values of raw to the types of the matching fields of the struct t.
+func coerceMap(raw map[string]any, t reflect.Type, format Format) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if key, ok := rawKey(raw, sf, format); ok {
+			raw[key] = coerceValue(raw[key], sf.Type, format)
+		}
+	}
+}
+
+// coerceValue coerces v to the type t, descending into structs, slices and maps.
+func coerceValue(v any, t reflect.Type, format Format) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if reflect.PointerTo(t).Implements(textUnmarshalerType) {
+		return v
+	}
+	if _, ok := parser(t); ok {
+		return v
+	}
+
+	switch val := v.(type) {
+	case map[string]any:
+		switch t.Kind() {
+		case reflect.Struct:
+			coerceMap(val, t, format)
+		case reflect.Map:
+			for k := range val {
+				val[k] = coerceValue(val[k], t.Elem(), format)
+			}
+		}
+		return val
+	case []any:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i := range val {
+				val[i] = coerceValue(val[i], t.Elem(), format)
+			}
+		}
+		return val
+	}
+
+	return coerceScalar(v, t.Kind())
+}
+
+// coerceScalar converts the scalar v for a field of the given kind according to the
+// table documented at SetCoerceScalars. Other values are returned unchanged.
+func coerceScalar(v any, kind reflect.Kind) any {
+	switch kind {
+	case reflect.Bool:
+		switch val := v.(type) {
+		case string:
+			if b, err := strconv.ParseBool(val); err == nil {
+				return b
+			}
+		default:
+			if f, ok := number(v); ok && (f == 0 || f == 1) {
+				return f == 1
+			}
+		}
+	case reflect.String:
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b)
+		}
+		if f, ok := number(v); ok {
+			return formatNumber(v, f)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch val := v.(type) {
+		case string:
+			if i, err := strconv.ParseInt(val, 10, 64); err == nil {
+				return i
+			}
+			if u, err := strconv.ParseUint(val, 10, 64); err == nil {
+				return u
+			}
+		case bool:
+			if val {
+				return 1
+			}
+			return 0
+		}
+	case reflect.Float32, reflect.Float64:
+		if s, ok := v.(string); ok {
+			if f, err := strconv.ParseFloat(s, 64); err == nil {
+				return f
+			}
+		}
+	}
+
+	return v
+}
+
+// number returns the value of v as float64 if it is a number as produced by the decoders.
+func number(v any) (float64, bool) {
+	switch val := v.(type) {
+	case int:
+		return float64(val), true
+	case int64:
+		return float64(val), true
+	case uint64:
+		return float64(val), true
+	case float64:
+		return val, true
+	case json.Number:
+		f, err := val.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// formatNumber returns the shortest decimal representation of the number v, whose value is f.
+func formatNumber(v any, f float64) string {
+	switch val := v.(type) {
+	case int:
+		return strconv.Itoa(val)
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case uint64:
+		return strconv.FormatUint(val, 10)
+	case json.Number:
+		if _, err := val.Int64(); err == nil {
+			return val.String()
+		}
+		if _, err := strconv.ParseUint(val.String(), 10, 64); err == nil {
+			return val.String()
+		}
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// SetCoerceScalars controls whether scalar values of file sources whose type doesn't
+// match the type of their field, e.g. true for a string field or 1 for a bool field,
+// are converted before decoding, so JSON, YAML and TOML behave the same:
+//
+//	field    value                                     result
+//	bool     number 0 or 1                             false or true
+//	bool     string accepted by strconv.ParseBool      the parsed bool
+//	string   bool                                      "true" or "false"
+//	string   number                                    its shortest decimal representation
+//	integer  string of an integer                      the integer
+//	integer  bool                                      1 for true, 0 for false
+//	float    string of a number                        the number
+//
+// All other values are left to the decoder. Fields of types with a registered parser
+// or an UnmarshalText method are never coerced. Coercion applies to sources decoding
+// a single file, block or reader in JSON, YAML or TOML.
+func (pr *Primordius) SetCoerceScalars(enabled bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.coerceScalars = enabled
+}
@@ -0,0 +1,126 @@
+package primordius
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func Test_coerceScalar(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		kind reflect.Kind
+		want any
+	}{
+		{"one to bool", 1, reflect.Bool, true},
+		{"zero float to bool", 0.0, reflect.Bool, false},
+		{"two to bool", 2, reflect.Bool, 2},
+		{"string to bool", "TRUE", reflect.Bool, true},
+		{"invalid string to bool", "yes", reflect.Bool, "yes"},
+		{"bool to string", true, reflect.String, "true"},
+		{"int to string", int64(42), reflect.String, "42"},
+		{"float to string", 1.50, reflect.String, "1.5"},
+		{"large float to string", 1e21, reflect.String, "1000000000000000000000"},
+		{"JSON number to string", json.Number("9007199254740993"), reflect.String, "9007199254740993"},
+		{"large JSON number to string", json.Number("18446744073709551615"), reflect.String, "18446744073709551615"},
+		{"JSON float to string", json.Number("1.50"), reflect.String, "1.5"},
+		{"JSON number to bool", json.Number("1"), reflect.Bool, true},
+		{"JSON number to int", json.Number("42"), reflect.Int, json.Number("42")},
+		{"string to int", "-42", reflect.Int, int64(-42)},
+		{"large string to uint", "18446744073709551615", reflect.Uint64, uint64(18446744073709551615)},
+		{"invalid string to int", "42x", reflect.Int, "42x"},
+		{"bool to int", true, reflect.Int16, 1},
+		{"string to float", "1e3", reflect.Float64, 1000.0},
+		{"matching type", "a", reflect.String, "a"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := coerceScalar(tc.v, tc.kind); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("coerceScalar() = %#v, want %#v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_SetCoerceScalars(t *testing.T) {
+	type server struct {
+		Port    int  `json:"port" yaml:"port" toml:"port"`
+		Enabled bool `json:"enabled" yaml:"enabled" toml:"enabled"`
+	}
+	type target struct {
+		Name    string            `json:"name" yaml:"name" toml:"name"`
+		Debug   *bool             `json:"debug" yaml:"debug" toml:"debug"`
+		Ratio   float64           `json:"ratio" yaml:"ratio" toml:"ratio"`
+		Timeout time.Duration     `json:"timeout" yaml:"timeout" toml:"timeout"`
+		Servers []server          `json:"servers" yaml:"servers" toml:"servers"`
+		Labels  map[string]string `json:"labels" yaml:"labels" toml:"labels"`
+		Tags    []string          `json:"tags" yaml:"tags" toml:"tags"`
+	}
+	debug := true
+	want := target{
+		Name:    "true",
+		Debug:   &debug,
+		Ratio:   0.5,
+		Timeout: 5,
+		Servers: []server{{Port: 80, Enabled: true}, {Port: 443}},
+		Labels:  map[string]string{"version": "2"},
+		Tags:    []string{"1", "false"},
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		content string
+	}{
+		{"json", FormatJSON, `{"name": true, "debug": 1, "ratio": "0.5", "timeout": 5,
+			"servers": [{"port": "80", "enabled": "true"}, {"port": 443, "enabled": 0}],
+			"labels": {"version": 2}, "tags": [1, false]}`},
+		{"yaml", FormatYAML, "name: true\ndebug: 1\nratio: '0.5'\ntimeout: 5\nservers:\n  - port: '80'\n    enabled: 'true'\n" +
+			"  - port: 443\n    enabled: 0\nlabels:\n  version: 2\ntags: [1, false]"},
+		{"toml", FormatTOML, "name = true\ndebug = 1\nratio = \"0.5\"\ntimeout = 5\ntags = [1, false]\n[labels]\nversion = 2\n" +
+			"[[servers]]\nport = \"80\"\nenabled = \"true\"\n[[servers]]\nport = 443\nenabled = 0\n"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.FromContent([]byte(tc.content), tc.format)
+			if err := pr.Process(); err == nil {
+				t.Fatal("Process() without coercion error = nil, want error")
+			}
+
+			got = target{}
+			pr.SetCoerceScalars(true)
+			if err := pr.Process(); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Process() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_SetCoerceScalars_numbers(t *testing.T) {
+	type target struct {
+		Name string `json:"name"`
+		Int  int64  `json:"int"`
+		Uint uint64 `json:"uint"`
+	}
+	want := target{Name: "true", Int: 9007199254740993, Uint: 18446744073709551615}
+
+	var got target
+	pr := New(&got)
+	pr.SetCoerceScalars(true)
+	pr.FromContent([]byte(`{"name": true, "int": 9007199254740993, "uint": 18446744073709551615}`), FormatJSON)
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if got != want {
+		t.Errorf("Process() got = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,60 @@
+package primordius
+
+import "sync"
+
+// loadResult is the outcome of loading the raw content of a single source.
+type loadResult struct {
+	content []byte
+	format  Format
+	err     error
+}
+
+// SetConcurrentLoading makes Process load the raw content of all sources decoding
+// files, blocks or remote content, e.g. FromYAMLFile or FromHTTP, concurrently before
+// any source is applied. The loaded content is then applied to the target one source
+// after another in the order the sources were added, like all other sources, so the
+// precedence of sources is unchanged. This reduces the time Process takes if several
+// slow sources, such as remote ones, are registered.
+// Sources must not depend on each other's loading when this is enabled.
+func (pr *Primordius) SetConcurrentLoading(enabled bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.concurrentLoading = enabled
+}
+
+// preload loads the content of all loading sources concurrently and returns the
+// results by source index. It does not touch the target.
+func (pr *Primordius) preload() map[int]loadResult {
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		results = make(map[int]loadResult)
+	)
+	for i, s := range pr.sources {
+		ls, ok := s.(loadingSource)
+		if !ok {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, ls loadingSource) {
+			defer wg.Done()
+			cont, format, err := ls.load()
+			mu.Lock()
+			defer mu.Unlock()
+			results[i] = loadResult{content: cont, format: format, err: err}
+		}(i, ls)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// load returns the raw content of ls, registered at index i, using the result of
+// preload if there is one.
+func (pr *Primordius) load(i int, ls loadingSource) ([]byte, Format, error) {
+	if lr, ok := pr.loaded[i]; ok {
+		return lr.content, lr.format, lr.err
+	}
+
+	return ls.load()
+}
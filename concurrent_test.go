@@ -0,0 +1,117 @@
+package primordius
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// barrierSource is a loading source whose load only succeeds if all loads of the
+// same barrier are running at the same time.
+type barrierSource struct {
+	content string
+	err     error
+	barrier *barrier
+}
+
+type barrier struct {
+	mu      sync.Mutex
+	waiting int
+	total   int
+	all     chan struct{}
+}
+
+func newBarrier(total int) *barrier {
+	return &barrier{total: total, all: make(chan struct{})}
+}
+
+func (bs *barrierSource) ToTarget(t any) error {
+	return decodeSource(bs, t)
+}
+
+func (bs *barrierSource) load() ([]byte, Format, error) {
+	b := bs.barrier
+	b.mu.Lock()
+	b.waiting++
+	if b.waiting == b.total {
+		close(b.all)
+	}
+	b.mu.Unlock()
+
+	select {
+	case <-b.all:
+	case <-time.After(time.Second):
+		return nil, "", errors.New("loaded sequentially")
+	}
+	if bs.err != nil {
+		return nil, "", bs.err
+	}
+	return []byte(bs.content), FormatJSON, nil
+}
+
+func TestPrimordius_SetConcurrentLoading(t *testing.T) {
+	type target struct {
+		Host string
+		Port int
+		Tags []string
+	}
+	errLoad := errors.New("load failed")
+
+	tests := []struct {
+		name          string
+		contents      []string
+		loadErr       error
+		collectErrors bool
+		want          target
+		wantErr       error
+	}{
+		{
+			name:     "later sources take precedence",
+			contents: []string{`{"Host": "a", "Port": 1}`, `{"Host": "b"}`, `{"Tags": ["x"]}`},
+			want:     target{Host: "b", Port: 1, Tags: []string{"x"}},
+		},
+		{
+			name:     "load error is returned in order",
+			contents: []string{`{"Host": "a"}`, `{"Host": "b"}`},
+			loadErr:  errLoad,
+			want:     target{Host: "a"},
+			wantErr:  errLoad,
+		},
+		{
+			name:          "load error is collected",
+			contents:      []string{`{"Host": "a"}`, `{"Host": "b"}`, `{"Port": 2}`},
+			loadErr:       errLoad,
+			collectErrors: true,
+			want:          target{Host: "a", Port: 2},
+			wantErr:       errLoad,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.SetConcurrentLoading(true)
+			pr.SetCollectErrors(tc.collectErrors)
+			b := newBarrier(len(tc.contents))
+			for i, c := range tc.contents {
+				s := &barrierSource{content: c, barrier: b}
+				if i == 1 {
+					s.err = tc.loadErr
+				}
+				pr.AddSource(s)
+			}
+			pr.AddSource(&envSource{prefix: "CONCURRENT_TEST_", conv: pr.conv})
+
+			err := pr.Process()
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("Process() error = %v, want %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package primordius
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// ConsulKV is the subset of a Consul client required by the Consul source. Wrap
+	// your client to satisfy it, e.g. for github.com/hashicorp/consul/api:
+	//
+	//	func (a adapter) List(ctx context.Context, prefix string) (map[string][]byte, error) {
+	//		pairs, _, err := a.c.KV().List(prefix, (&api.QueryOptions{}).WithContext(ctx))
+	//		if err != nil {
+	//			return nil, err
+	//		}
+	//		entries := make(map[string][]byte, len(pairs))
+	//		for _, p := range pairs {
+	//			entries[p.Key] = p.Value
+	//		}
+	//		return entries, nil
+	//	}
+	ConsulKV interface {
+		// List returns the values of all keys starting with prefix, by key.
+		List(ctx context.Context, prefix string) (map[string][]byte, error)
+	}
+	consulSource struct {
+		kv     ConsulKV
+		prefix string
+		conv   *converter
+	}
+)
+
+func (cs *consulSource) ToTarget(t any) error {
+	entries, err := cs.kv.List(context.Background(), cs.prefix)
+	if err != nil {
+		return fmt.Errorf("listing Consul keys under %s: %w", cs.prefix, err)
+	}
+
+	return cs.conv.applyTagged(t, prefixedValues(entries, cs.prefix))
+}
+
+func (cs *consulSource) describe() string {
+	return cs.prefix
+}
+
+// FromConsul adds a Source to pr which reads all keys under prefix from the Consul KV
+// store, e.g. "my-app/" for "my-app/port". The keys are matched against the 'env' tag
+// without the prefix, e.g. `env:"port"`, and values are converted like those of
+// environment variables. The keys are fetched once per call to Process.
+func (pr *Primordius) FromConsul(kv ConsulKV, prefix string) {
+	pr.AddSource(&consulSource{kv: kv, prefix: prefix, conv: pr.conv})
+}
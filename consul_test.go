@@ -0,0 +1,64 @@
+package primordius
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeConsulKV map[string]string
+
+func (fc fakeConsulKV) List(_ context.Context, prefix string) (map[string][]byte, error) {
+	if prefix == "unavailable/" {
+		return nil, errors.New("consul: connection refused")
+	}
+	entries := make(map[string][]byte)
+	for key, val := range fc {
+		if strings.HasPrefix(key, prefix) {
+			entries[key] = []byte(val)
+		}
+	}
+	return entries, nil
+}
+
+func Test_consulSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `env:"host"`
+		Port int    `env:"db/port"`
+	}
+
+	kv := fakeConsulKV{
+		"app/":            "",
+		"app/host":        "example.com",
+		"app/db/":         "",
+		"app/db/port":     "5432",
+		"other/host":      "ignored",
+		"invalid/db/port": "high",
+	}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		want    target
+		wantErr bool
+	}{
+		{"prefix with slash", "app/", target{"example.com", 5432}, false},
+		{"prefix without slash", "app", target{"example.com", 5432}, false},
+		{"nothing under prefix", "missing/", target{}, false},
+		{"invalid value", "invalid/", target{}, true},
+		{"unavailable", "unavailable/", target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&consulSource{kv: kv, prefix: tc.prefix}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
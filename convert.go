@@ -0,0 +1,890 @@
+package primordius
+
+import (
+	"encoding"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	// DefaultUnsetSentinel is a suggested value for SetUnsetSentinel.
+	DefaultUnsetSentinel = "__unset__"
+	// DefaultDelimiter separates the elements of slice values and the pairs of map values.
+	DefaultDelimiter = ","
+	// DefaultKeyValueDelimiter separates the keys from the values of the pairs of map values.
+	DefaultKeyValueDelimiter = "="
+	// DefaultEnvSeparator separates the prefix of env sources from the keys.
+	DefaultEnvSeparator = "_"
+	// DefaultMaxDepth is the default maximum nesting depth of structs the env and args
+	// sources descend into.
+	DefaultMaxDepth = 32
+)
+
+var (
+	ErrFieldNotSettable = errors.New("field cannot be set")
+	ErrRejectedValue    = errors.New("value rejected")
+	ErrMaxDepth         = errors.New("maximum nesting depth exceeded")
+	ErrPointerCycle     = errors.New("pointer cycle detected")
+)
+
+var (
+	locationType = reflect.TypeOf((*time.Location)(nil))
+	timeType     = reflect.TypeOf(time.Time{})
+	durationType = reflect.TypeOf(time.Duration(0))
+
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	envUnmarshalerType  = reflect.TypeOf((*EnvUnmarshaler)(nil)).Elem()
+)
+
+// EnvUnmarshaler is implemented by types parsing their own representation in env vars
+// and other string values, e.g. of args and flags. It is preferred over
+// encoding.TextUnmarshaler and the built-in conversions, so types can use a different
+// representation than in text-based formats. Like UnmarshalText, UnmarshalEnv is called
+// on a pointer to the field.
+type EnvUnmarshaler interface {
+	UnmarshalEnv(val string) error
+}
+
+// epochMillisThreshold separates Unix timestamps in seconds from those in milliseconds.
+// As seconds, it corresponds to the year 5138; as milliseconds, to 1973.
+const epochMillisThreshold = 1e11
+
+// converter holds the settings shared by all sources which convert string values
+// into fields, such as the env and args sources. A nil *converter uses the defaults.
+type converter struct {
+	// unsetSentinel resets a field to its zero value when encountered as its value.
+	// An empty sentinel is never matched.
+	unsetSentinel string
+	// errorOnUnsettable makes tagged fields which cannot be set an error instead of skipping them.
+	errorOnUnsettable bool
+	// maxValueLength is the maximum length of values in bytes; 0 means unlimited.
+	maxValueLength int
+	// rejectControlChars makes values containing control characters an error.
+	rejectControlChars bool
+	// depth is the maximum nesting depth of structs; 0 means DefaultMaxDepth.
+	depth int
+	// envSeparator is inserted between the prefix of env sources and the keys.
+	envSeparator string
+	// ignoreUnknownFlags makes flag sources skip flags not matching any field instead of failing.
+	ignoreUnknownFlags bool
+	// keyMapper computes the keys of fields without a key in their env tag; nil disables this.
+	keyMapper KeyMapper
+	// strictDotEnv makes malformed lines of dotenv files an error instead of skipping them.
+	strictDotEnv bool
+	// envAllowlist holds the names of the only env vars env sources read; nil means all.
+	envAllowlist map[string]bool
+	// commandsEnabled allows running the commands of cmd tags.
+	commandsEnabled bool
+	// commandTimeout is the time a command may run; 0 means DefaultCommandTimeout.
+	commandTimeout time.Duration
+	// etcdTimeout is the time etcd sources may take to fetch keys; 0 means DefaultEtcdTimeout.
+	etcdTimeout time.Duration
+	// redisTimeout is the time Redis sources may take to read keys; 0 means DefaultRedisTimeout.
+	redisTimeout time.Duration
+	// bestEffort turns values which cannot be converted into warnings instead of errors.
+	bestEffort bool
+	// warnings holds the non-fatal issues encountered during Process.
+	warnings []error
+}
+
+// KeyMapper computes the key of a field from the names of the fields on the path to it,
+// e.g. []string{"Database", "Host"} for the field Host of the struct field Database.
+// An empty key skips the field.
+type KeyMapper func(fieldPath []string) string
+
+// visit identifies a pointer followed while descending into nested structs.
+type visit struct {
+	typ reflect.Type
+	ptr uintptr
+}
+
+// SetUnsetSentinel sets a value which, when provided for a field by a source,
+// resets the field to its zero value instead, e.g. DefaultUnsetSentinel. This allows
+// a source with higher priority to remove a value set by a source with lower priority.
+// The env and args sources recognize the sentinel for fields of any type; for all
+// other sources, only string values are recognized. An empty sentinel, the default,
+// disables this behavior.
+func (pr *Primordius) SetUnsetSentinel(sentinel string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.unsetSentinel = sentinel
+}
+
+// SetErrorOnUnsettable controls how the env and args sources treat tagged fields which
+// cannot be set, e.g. because they are unexported. By default, such fields are skipped;
+// if enabled, processing fails with an error wrapping ErrFieldNotSettable instead,
+// which surfaces mistakes in the struct definition early.
+func (pr *Primordius) SetErrorOnUnsettable(enabled bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.errorOnUnsettable = enabled
+}
+
+// SetValueGuards hardens the env and args sources against malformed or hostile input.
+// Values longer than maxLength bytes and, if rejectControlChars is true, values
+// containing control characters such as newlines or escape sequences are rejected
+// with an error wrapping ErrRejectedValue. A maxLength of 0 disables the length check.
+func (pr *Primordius) SetValueGuards(maxLength int, rejectControlChars bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.maxValueLength = maxLength
+	pr.conv.rejectControlChars = rejectControlChars
+}
+
+// SetEnvSeparator sets the separator inserted between the prefix of env sources and the
+// keys from env tags, DefaultEnvSeparator by default. Supply an empty string to concatenate
+// prefix and key literally, as done by earlier versions, where the prefix "APP" and the
+// key "PORT" resulted in "APPPORT".
+func (pr *Primordius) SetEnvSeparator(sep string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.envSeparator = sep
+}
+
+// envPrefix returns prefix followed by the env separator, unless prefix is empty or
+// already ends with the separator.
+func (c *converter) envPrefix(prefix string) string {
+	sep := DefaultEnvSeparator
+	if c != nil {
+		sep = c.envSeparator
+	}
+	if prefix == "" || strings.HasSuffix(prefix, sep) {
+		return prefix
+	}
+	return prefix + sep
+}
+
+// SetKeyMapper registers fn to compute the keys of fields whose env tag doesn't name
+// a key, e.g. UpperSnakeCase, so naming conventions needn't be repeated in tags. It
+// is used by all sources matching keys against the env tag, such as env, args and
+// flags. Structs are still descended into rather than mapped. Supply nil to only
+// consider fields with a key in their env tag, the default.
+func (pr *Primordius) SetKeyMapper(fn KeyMapper) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.keyMapper = fn
+}
+
+// mappedKey returns the key computed by the KeyMapper of c for the field f at path,
+// or an empty string if there is no KeyMapper or f is to be descended into.
+func (c *converter) mappedKey(f reflect.Value, path string) string {
+	if c == nil || c.keyMapper == nil {
+		return ""
+	}
+	t := f.Type()
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if hasExportedFields(t) && !convertible(f.Type()) {
+		return ""
+	}
+	return c.keyMapper(strings.Split(path, "."))
+}
+
+// UpperSnakeCase is a KeyMapper joining the field names converted to upper snake
+// case with underscores, e.g. "DATABASE_MAX_CONNS" for Database.MaxConns. Acronyms
+// are kept together, e.g. "BASE_URL" for BaseURL.
+func UpperSnakeCase(fieldPath []string) string {
+	parts := make([]string, 0, len(fieldPath))
+	for _, name := range fieldPath {
+		parts = append(parts, strings.ToUpper(snakeCase(name)))
+	}
+	return strings.Join(parts, "_")
+}
+
+// snakeCase inserts underscores at the word boundaries of the camel case name.
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var sb strings.Builder
+	for i, r := range runes {
+		if i > 0 && unicode.IsUpper(r) {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || nextLower && unicode.IsUpper(runes[i-1]) {
+				sb.WriteByte('_')
+			}
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
+
+// SetEnvAllowlist restricts the env sources to the env vars with the given names,
+// including their prefix, e.g. "APP_PORT". Fields whose env vars are not on the list
+// are skipped as if the env vars were not set, which keeps the set of consumed env vars
+// explicitly enumerated. Calling SetEnvAllowlist without names removes the restriction.
+func (pr *Primordius) SetEnvAllowlist(names ...string) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if len(names) == 0 {
+		pr.conv.envAllowlist = nil
+		return
+	}
+	pr.conv.envAllowlist = make(map[string]bool, len(names))
+	for _, name := range names {
+		pr.conv.envAllowlist[name] = true
+	}
+}
+
+// envAllowed reports whether the env var name may be read.
+func (c *converter) envAllowed(name string) bool {
+	return c == nil || c.envAllowlist == nil || c.envAllowlist[name]
+}
+
+// SetBestEffort controls whether values of the env and args sources which cannot be
+// converted to the type of their field, e.g. a slice with an element that doesn't parse,
+// fail processing. If enabled, such values are skipped, leaving the field unchanged,
+// and reported by Warnings instead.
+func (pr *Primordius) SetBestEffort(enabled bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.bestEffort = enabled
+}
+
+// Warnings returns the non-fatal issues encountered during the last call to Process,
+// such as values skipped in best-effort mode (see SetBestEffort) or the use of keys
+// marked as deprecated using the deprecated option of the env tag.
+func (pr *Primordius) Warnings() []error {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	return pr.conv.warnings
+}
+
+// warn records a non-fatal issue.
+func (c *converter) warn(err error) {
+	c.warnings = append(c.warnings, err)
+}
+
+// convertError returns err if c is not in best-effort mode; otherwise, it records err
+// as warning and returns nil.
+func (c *converter) convertError(err error) error {
+	if !c.bestEffort {
+		return err
+	}
+	c.warn(err)
+	return nil
+}
+
+// SetMaxDepth sets the maximum nesting depth of structs the env and args sources
+// descend into. Deeper nesting results in an error wrapping ErrMaxDepth instead of
+// excessive recursion. Non-positive values select DefaultMaxDepth.
+func (pr *Primordius) SetMaxDepth(depth int) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.depth = depth
+}
+
+func (c *converter) maxDepth() int {
+	if c.depth <= 0 {
+		return DefaultMaxDepth
+	}
+	return c.depth
+}
+
+// guard returns an error if val violates the value guards of c.
+func (c *converter) guard(path, key, val string) error {
+	if c.maxValueLength > 0 && len(val) > c.maxValueLength {
+		return fmt.Errorf("%w: field %s: value of key %s exceeds %d bytes", ErrRejectedValue, path, key, c.maxValueLength)
+	}
+	if !c.rejectControlChars {
+		return nil
+	}
+	for i, r := range val {
+		if unicode.IsControl(r) {
+			return fmt.Errorf("%w: field %s: value of key %s contains control character %U at position %d",
+				ErrRejectedValue, path, key, r, i)
+		}
+	}
+	return nil
+}
+
+// keyValues provides the values applyTagged assigns to fields by key.
+type keyValues interface {
+	// lookup returns the value for key, requested for the field at path, and
+	// whether it exists.
+	lookup(path, key string) (string, bool)
+	// keys returns all available keys.
+	keys() []string
+}
+
+// optionedValues is implemented by keyValues adjusting how their values are applied.
+type optionedValues interface {
+	// defaultOptions returns tag options applying to all fields whose tags don't set them.
+	defaultOptions() tagOptions
+	// requireValues reports whether fields marked as required must have a value.
+	requireValues() bool
+}
+
+// valueMap is a keyValues backed by a map.
+type valueMap map[string]string
+
+func (vm valueMap) lookup(_, key string) (string, bool) {
+	val, ok := vm[key]
+	return val, ok
+}
+
+func (vm valueMap) keys() []string {
+	keys := make([]string, 0, len(vm))
+	for key := range vm {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// prefixedValues returns entries keyed by the rest of their keys following prefix
+// and a "/", e.g. "db/port" for "my-app/db/port" and the prefix "my-app". Keys ending
+// in "/", which name folders of key-value stores, are skipped.
+func prefixedValues(entries map[string][]byte, prefix string) valueMap {
+	values := make(valueMap, len(entries))
+	for key, val := range entries {
+		key = strings.TrimPrefix(strings.TrimPrefix(key, prefix), "/")
+		if key == "" || strings.HasSuffix(key, "/") {
+			continue
+		}
+		values[key] = string(val)
+	}
+	return values
+}
+
+// applyTagged sets every field of the struct spec points to whose env tag names a
+// key known to kv, converting the value according to the field's type. Untagged
+// fields of struct type and non-nil pointers to structs are descended into; a pointer
+// cycle results in an error wrapping ErrPointerCycle.
+// A key ending in "*", e.g. "ROUTE_*", populates a map field with all values whose
+// keys share the prefix, keyed by the remainder of the key. A bool field with the
+// presence option is set to true if its key exists, regardless of the value. The
+// fields of a struct field with the logfmt option are set from key=value pairs, those
+// of a struct field with the dsn option from the components of a URL-shaped DSN.
+func (c *converter) applyTagged(spec any, kv keyValues) error {
+	if c == nil {
+		c = &converter{}
+	}
+	valueOf := reflect.ValueOf(spec)
+
+	if valueOf.Kind() != reflect.Pointer {
+		return ErrInvalidSpecification
+	}
+	s := valueOf.Elem()
+	if s.Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	visited := map[visit]bool{{typ: valueOf.Type(), ptr: valueOf.Pointer()}: true}
+	return c.applyStruct(s, "", kv, 0, visited)
+}
+
+// applyStruct implements applyTagged for the struct s at path, which is nested
+// depth levels deep. visited holds the pointers followed to reach s.
+func (c *converter) applyStruct(s reflect.Value, path string, kv keyValues, depth int, visited map[visit]bool) error {
+	t := s.Type()
+	ov, _ := kv.(optionedValues)
+	for i := 0; i < s.NumField(); i++ {
+		f := s.Field(i)
+
+		if !f.IsValid() {
+			continue
+		}
+		fieldPath := t.Field(i).Name
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		key, opts := parseTag(t.Field(i).Tag.Get(tagName))
+		if key == "" && t.Field(i).IsExported() {
+			if key = c.mappedKey(f, fieldPath); key == "" {
+				if err := c.applyNested(f, fieldPath, kv, depth, visited); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if key == "" || key == "-" {
+			continue
+		}
+		if !f.CanSet() {
+			if c.errorOnUnsettable {
+				return unsettableError(t.Field(i))
+			}
+			continue
+		}
+		if strings.HasSuffix(key, "*") {
+			if err := c.setWildcard(f, fieldPath, strings.TrimSuffix(key, "*"), kv); err != nil {
+				return err
+			}
+			continue
+		}
+		val, exists := kv.lookup(fieldPath, key)
+		if !exists {
+			if ov != nil && ov.requireValues() && isRequired(t.Field(i), opts) {
+				return fmt.Errorf("%w: %s (%s not set)", ErrMissingRequired, fieldPath, key)
+			}
+			continue
+		}
+		if ov != nil {
+			opts = opts.withDefaults(ov.defaultOptions())
+		}
+		if err := c.assign(f, fieldPath, key, val, opts); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assign converts val, found under key, according to the options of the tag and the
+// type of the field f and assigns it.
+func (c *converter) assign(f reflect.Value, path, key, val string, opts tagOptions) error {
+	if err := c.guard(path, key, val); err != nil {
+		return err
+	}
+	if opts.has("deprecated") {
+		c.warn(deprecationWarning(path, key, opts["deprecated"]))
+	}
+	if c.unsetSentinel != "" && val == c.unsetSentinel {
+		f.Set(reflect.Zero(f.Type()))
+		return nil
+	}
+	if opts.has("logfmt") {
+		return c.setLogfmt(f, path, val)
+	}
+	if opts.has("dsn") {
+		return setDSN(f, path, val)
+	}
+	if opts.has("presence") {
+		if f.Kind() != reflect.Bool {
+			return fmt.Errorf("field %s: presence option requires a bool field", path)
+		}
+		f.SetBool(true)
+		return nil
+	}
+	if opts.has("base64") {
+		if f.Kind() != reflect.Slice || f.Type().Elem().Kind() != reflect.Uint8 {
+			return fmt.Errorf("field %s: base64 option requires a []byte field", path)
+		}
+		b, err := base64.StdEncoding.DecodeString(val)
+		if err != nil {
+			return c.convertError(fmt.Errorf("field %s: decoding base64: %w", path, err))
+		}
+		f.SetBytes(b)
+		return nil
+	}
+	if f.Kind() == reflect.String {
+		val = opts.transform(val)
+	}
+	if f.Kind() == reflect.Map && (opts.has("delimiter") || opts.has("kvdelimiter")) {
+		if err := setMap(f, val, opts.get("delimiter", DefaultDelimiter), opts.get("kvdelimiter", DefaultKeyValueDelimiter)); err != nil {
+			return c.convertError(fmt.Errorf("field %s: %w", path, err))
+		}
+		return nil
+	}
+	if layout := opts.get("layout", ""); layout != "" && (f.Type() == timeType || f.Type() == reflect.PointerTo(timeType)) {
+		if err := setTime(f, val, layout); err != nil {
+			return c.convertError(fmt.Errorf("field %s: %w", path, err))
+		}
+		return nil
+	}
+	if f.Kind() == reflect.Slice && f.Type().Elem().Kind() != reflect.Uint8 && opts.has("delimiter") {
+		if err := setSlice(f, val, opts.get("delimiter", DefaultDelimiter)); err != nil {
+			return c.convertError(fmt.Errorf("field %s: %w", path, err))
+		}
+		return nil
+	}
+
+	if err := setValue(f, val); err != nil {
+		return c.convertError(fmt.Errorf("field %s: %w", path, err))
+	}
+	return nil
+}
+
+// applyNested descends into the untagged field f at path if it is a struct or a
+// non-nil pointer to a struct, guarding against excessive nesting and pointer cycles.
+func (c *converter) applyNested(f reflect.Value, path string, kv keyValues, depth int, visited map[visit]bool) error {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() || !hasExportedFields(f.Type().Elem()) {
+			return nil
+		}
+		v := visit{typ: f.Type(), ptr: f.Pointer()}
+		if visited[v] {
+			return fmt.Errorf("%w: field %s", ErrPointerCycle, path)
+		}
+		visited[v] = true
+		defer delete(visited, v)
+		f = f.Elem()
+	}
+	if !hasExportedFields(f.Type()) {
+		return nil
+	}
+	if depth+1 > c.maxDepth() {
+		return fmt.Errorf("%w: field %s is nested more than %d levels deep", ErrMaxDepth, path, c.maxDepth())
+	}
+
+	return c.applyStruct(f, path, kv, depth+1, visited)
+}
+
+// deprecationWarning returns the warning for the use of the deprecated key of the field
+// at path, naming the replacement if known.
+func deprecationWarning(path, key, replacement string) error {
+	if replacement != "" {
+		return fmt.Errorf("field %s: key %s is deprecated, use %s instead", path, key, replacement)
+	}
+	return fmt.Errorf("field %s: key %s is deprecated", path, key)
+}
+
+// structTarget returns the struct f, allocating it if f is a nil pointer to a struct.
+// It returns an error mentioning the tag option if f is neither.
+func structTarget(f reflect.Value, path, option string) (reflect.Value, error) {
+	if f.Kind() == reflect.Pointer && f.Type().Elem().Kind() == reflect.Struct {
+		if f.IsNil() {
+			f.Set(reflect.New(f.Type().Elem()))
+		}
+		f = f.Elem()
+	}
+	if f.Kind() != reflect.Struct {
+		return reflect.Value{}, fmt.Errorf("field %s: %s option requires a struct field", path, option)
+	}
+	return f, nil
+}
+
+// setWildcard adds all values of kv whose keys start with prefix to the map f,
+// keyed by the remainder of their keys.
+func (c *converter) setWildcard(f reflect.Value, path, prefix string, kv keyValues) error {
+	if f.Kind() != reflect.Map || f.Type().Key().Kind() != reflect.String {
+		return fmt.Errorf("field %s: wildcard key requires a map with string keys", path)
+	}
+
+	keys := kv.keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		name := strings.TrimPrefix(key, prefix)
+		if name == key || name == "" {
+			continue
+		}
+		val, exists := kv.lookup(path, key)
+		if !exists {
+			continue
+		}
+		if err := c.guard(path, key, val); err != nil {
+			return err
+		}
+		elem := reflect.New(f.Type().Elem()).Elem()
+		if err := setValue(elem, val); err != nil {
+			if err := c.convertError(fmt.Errorf("field %s: key %s: %w", path, key, err)); err != nil {
+				return err
+			}
+			continue
+		}
+		if f.IsNil() {
+			f.Set(reflect.MakeMap(f.Type()))
+		}
+		f.SetMapIndex(reflect.ValueOf(name).Convert(f.Type().Key()), elem)
+	}
+
+	return nil
+}
+
+// unsettableError explains why the field sf cannot be set.
+func unsettableError(sf reflect.StructField) error {
+	if !sf.IsExported() {
+		return fmt.Errorf("%w: field %s is unexported", ErrFieldNotSettable, sf.Name)
+	}
+	return fmt.Errorf("%w: field %s is not addressable", ErrFieldNotSettable, sf.Name)
+}
+
+// resetUnset resets all string values of target matching the unset sentinel to
+// the empty string.
+func (c *converter) resetUnset(target any) error {
+	if c == nil || c.unsetSentinel == "" {
+		return nil
+	}
+
+	return walkStrings(reflect.ValueOf(target), "", make(map[visit]bool), func(_, val string) (string, error) {
+		if val == c.unsetSentinel {
+			return "", nil
+		}
+		return val, nil
+	})
+}
+
+// setValue parses val according to the type of f and assigns the result to f.
+func setValue(f reflect.Value, val string) error {
+	if parse, ok := parser(f.Type()); ok {
+		v, err := parse(val)
+		if err != nil {
+			return err
+		}
+		f.Set(v)
+		return nil
+	}
+	if ok, err := unmarshalEnv(f, val); ok {
+		return err
+	}
+	if f.Type() == locationType {
+		loc, err := time.LoadLocation(val)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(loc))
+		return nil
+	}
+	if f.Type() == durationType {
+		d, err := time.ParseDuration(val)
+		if err != nil {
+			return fmt.Errorf("%w, expected e.g. \"30s\" or \"1h30m\"", err)
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	if f.Type() == timeType {
+		t, err := parseTime(val)
+		if err != nil {
+			return err
+		}
+		f.Set(reflect.ValueOf(t))
+		return nil
+	}
+
+	if ok, err := unmarshalText(f, val); ok {
+		return err
+	}
+
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(val)
+	case reflect.Int:
+		fallthrough
+	case reflect.Int8:
+		fallthrough
+	case reflect.Int16:
+		fallthrough
+	case reflect.Int32:
+		fallthrough
+	case reflect.Int64:
+		// parsing according to the size of the field reports values out of its range
+		v, err := strconv.ParseInt(val, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetInt(v)
+	case reflect.Uint:
+		fallthrough
+	case reflect.Uint8:
+		fallthrough
+	case reflect.Uint16:
+		fallthrough
+	case reflect.Uint32:
+		fallthrough
+	case reflect.Uint64:
+		v, err := strconv.ParseUint(val, 10, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetUint(v)
+	case reflect.Bool:
+		v, err := strconv.ParseBool(val)
+		if err != nil {
+			return err
+		}
+		f.SetBool(v)
+	case reflect.Float32:
+		fallthrough
+	case reflect.Float64:
+		v, err := strconv.ParseFloat(val, f.Type().Bits())
+		if err != nil {
+			return err
+		}
+		f.SetFloat(v)
+	case reflect.Slice:
+		if f.Type().Elem().Kind() == reflect.Uint8 {
+			f.SetBytes([]byte(val))
+			break
+		}
+		return setSlice(f, val, DefaultDelimiter)
+	case reflect.Map:
+		return setMap(f, val, DefaultDelimiter, DefaultKeyValueDelimiter)
+	case reflect.Pointer:
+		// a fresh value is allocated, so values shared with other pointers are never modified
+		if _, ok := parser(f.Type().Elem()); !ok && f.Type().Elem().Kind() == reflect.Struct && f.Type().Elem() != timeType {
+			break
+		}
+		v := reflect.New(f.Type().Elem())
+		if err := setValue(v.Elem(), val); err != nil {
+			return err
+		}
+		f.Set(v)
+	}
+
+	return nil
+}
+
+// setSlice parses val as elements separated by delim, e.g. "/usr/bin:/bin" for the
+// delimiter ":", and assigns the resulting slice to f. Elements are converted according
+// to the element type of the slice.
+func setSlice(f reflect.Value, val, delim string) error {
+	parts := splitList(val, delim)
+	sl := reflect.MakeSlice(f.Type(), len(parts), len(parts))
+	for i, part := range parts {
+		if err := setValue(sl.Index(i), part); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	f.Set(sl)
+
+	return nil
+}
+
+// setMap parses val as pairs separated by delim, whose keys and values are separated
+// by kvDelim, e.g. "env=prod,team=core", and assigns the resulting map to f. Keys and
+// values are converted according to the types of the map.
+func setMap(f reflect.Value, val, delim, kvDelim string) error {
+	t := f.Type()
+	if !convertible(t.Key()) || !convertible(t.Elem()) {
+		return fmt.Errorf("unsupported map type %s", t)
+	}
+
+	m := reflect.MakeMap(t)
+	for _, pair := range splitList(val, delim) {
+		k, v, ok := strings.Cut(pair, kvDelim)
+		if !ok {
+			return fmt.Errorf("pair %q lacks key-value delimiter %q", pair, kvDelim)
+		}
+		key, elem := reflect.New(t.Key()).Elem(), reflect.New(t.Elem()).Elem()
+		if err := setValue(key, k); err != nil {
+			return fmt.Errorf("key %q: %w", k, err)
+		}
+		if err := setValue(elem, v); err != nil {
+			return fmt.Errorf("value of key %q: %w", k, err)
+		}
+		m.SetMapIndex(key, elem)
+	}
+	f.Set(m)
+
+	return nil
+}
+
+// convertible reports whether setValue can convert strings into values of type t
+// without splitting them into elements.
+func convertible(t reflect.Type) bool {
+	if _, ok := parser(t); ok {
+		return true
+	}
+	if t == locationType || t == durationType || t == timeType ||
+		reflect.PointerTo(t).Implements(textUnmarshalerType) || reflect.PointerTo(t).Implements(envUnmarshalerType) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool, reflect.Float32, reflect.Float64,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return true
+	case reflect.Pointer:
+		return convertible(t.Elem())
+	}
+	return false
+}
+
+// unmarshalText calls UnmarshalText if f, through its address, or the type pointed to by
+// f implements encoding.TextUnmarshaler, allocating nil pointers. It reports whether it did.
+func unmarshalText(f reflect.Value, val string) (bool, error) {
+	return unmarshalWith(f, textUnmarshalerType, func(u any) error {
+		return u.(encoding.TextUnmarshaler).UnmarshalText([]byte(val))
+	})
+}
+
+// unmarshalEnv calls UnmarshalEnv like unmarshalText calls UnmarshalText.
+func unmarshalEnv(f reflect.Value, val string) (bool, error) {
+	return unmarshalWith(f, envUnmarshalerType, func(u any) error {
+		return u.(EnvUnmarshaler).UnmarshalEnv(val)
+	})
+}
+
+// unmarshalWith calls fn with f, through its address, or a new value of the type pointed
+// to by f if it implements the interface type it; a new value is assigned to f only if
+// fn succeeds. It reports whether fn was called.
+func unmarshalWith(f reflect.Value, it reflect.Type, fn func(u any) error) (bool, error) {
+	switch {
+	case f.Kind() == reflect.Pointer && f.Type().Implements(it):
+		v := reflect.New(f.Type().Elem())
+		if err := fn(v.Interface()); err != nil {
+			return true, err
+		}
+		f.Set(v)
+		return true, nil
+	case f.CanAddr() && f.Addr().Type().Implements(it):
+		return true, fn(f.Addr().Interface())
+	}
+	return false, nil
+}
+
+// setTime parses val according to layout, e.g. "2006-01-02", and assigns the result
+// to f, which is a time.Time or a pointer to one.
+func setTime(f reflect.Value, val, layout string) error {
+	t, err := time.Parse(layout, val)
+	if err != nil {
+		return fmt.Errorf("%q does not match the layout %q", val, layout)
+	}
+	if f.Kind() == reflect.Pointer {
+		f.Set(reflect.ValueOf(&t))
+		return nil
+	}
+	f.Set(reflect.ValueOf(t))
+	return nil
+}
+
+// parseTime parses val as a Unix timestamp if it consists of digits only, in seconds or,
+// if it is large enough, in milliseconds. Otherwise, val is parsed as RFC 3339 timestamp.
+// Timestamps are returned in UTC.
+func parseTime(val string) (time.Time, error) {
+	if val != "" && strings.Trim(val, "0123456789") == "" {
+		epoch, err := strconv.ParseInt(val, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid Unix timestamp %q: %w", val, err)
+		}
+		if epoch >= epochMillisThreshold {
+			return time.UnixMilli(epoch).UTC(), nil
+		}
+		return time.Unix(epoch, 0).UTC(), nil
+	}
+
+	t, err := time.Parse(time.RFC3339, val)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%q is neither an RFC 3339 nor a Unix timestamp", val)
+	}
+	return t, nil
+}
+
+// splitList splits val into its elements separated by delim. An empty val has no
+// elements. If delim could also be the sign of a number ("-" or "+"), an occurrence
+// at the start of an element or directly following the exponent marker of a number,
+// as in "1e-5", is part of the element rather than a delimiter.
+func splitList(val, delim string) []string {
+	if val == "" {
+		return []string{}
+	}
+	if delim != "-" && delim != "+" {
+		return strings.Split(val, delim)
+	}
+
+	parts := make([]string, 0)
+	start := 0
+	for i := 0; i < len(val); i++ {
+		if val[i] != delim[0] || i == start || isExponentSign(val, i) {
+			continue
+		}
+		parts = append(parts, val[start:i])
+		start = i + 1
+	}
+
+	return append(parts, val[start:])
+}
+
+// isExponentSign reports whether the character at i of val is the sign of the
+// exponent of a number, i.e. it follows an 'e' or 'E' preceded by a digit.
+func isExponentSign(val string, i int) bool {
+	return i >= 2 && (val[i-1] == 'e' || val[i-1] == 'E') && val[i-2] >= '0' && val[i-2] <= '9'
+}
@@ -0,0 +1,925 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// envLevel implements EnvUnmarshaler as well as encoding.TextUnmarshaler, whose
+// result must not be used.
+type envLevel int
+
+func (l *envLevel) UnmarshalEnv(val string) error {
+	switch val {
+	case "low":
+		*l = 1
+	case "high":
+		*l = 2
+	default:
+		return fmt.Errorf("invalid level %q", val)
+	}
+	return nil
+}
+
+func (l *envLevel) UnmarshalText([]byte) error {
+	*l = -1
+	return nil
+}
+
+func Test_setValue(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone database not available: %s", err)
+	}
+
+	bigInt, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+
+	tests := []struct {
+		name    string
+		target  any
+		val     string
+		want    any
+		wantErr bool
+	}{
+		{"string", new(string), "abc", "abc", false},
+		{"int", new(int), "-42", -42, false},
+		{"invalid int", new(int), "abc", 0, true},
+		{"uint", new(uint16), "42", uint16(42), false},
+		{"int8 bounds", new(int8), "-128", int8(-128), false},
+		{"int8 overflow", new(int8), "9999", int8(0), true},
+		{"int32 overflow", new(int32), "2147483648", int32(0), true},
+		{"uint8 overflow", new(uint8), "256", uint8(0), true},
+		{"uint32 bounds", new(uint32), "4294967295", uint32(4294967295), false},
+		{"negative uint", new(uint), "-1", uint(0), true},
+		{"float32 overflow", new(float32), "1e39", float32(0), true},
+		{"bool", new(bool), "true", true, false},
+		{"float", new(float64), "1.5", 1.5, false},
+		{"location", new(*time.Location), "America/New_York", newYork, false},
+		{"unknown location", new(*time.Location), "Mars/Olympus_Mons", (*time.Location)(nil), true},
+		{"duration", new(time.Duration), "1m30s", 90 * time.Second, false},
+		{"durations", new([]time.Duration), "1s,2ms", []time.Duration{time.Second, 2 * time.Millisecond}, false},
+		{"duration without unit", new(time.Duration), "30", time.Duration(0), true},
+		{"RFC 3339 time", new(time.Time), "2024-05-01T12:30:00Z", time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), false},
+		{"epoch seconds", new(time.Time), "1714566600", time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC), false},
+		{"epoch milliseconds", new(time.Time), "1714566600500", time.Date(2024, 5, 1, 12, 30, 0, 5e8, time.UTC), false},
+		{"invalid time", new(time.Time), "May 1st", time.Time{}, true},
+		{"negative epoch", new(time.Time), "-1", time.Time{}, true},
+		{"text unmarshaler", new(net.IP), "192.168.0.1", net.ParseIP("192.168.0.1"), false},
+		{"invalid text", new(net.IP), "300.0.0.1", net.IP(nil), true},
+		{"text unmarshaler pointer", new(*big.Int), "123456789012345678901234567890", bigInt, false},
+		{"text unmarshaler elements", new([]net.IP), "::1,10.0.0.1", []net.IP{net.ParseIP("::1"), net.ParseIP("10.0.0.1")}, false},
+		{"env unmarshaler", new(envLevel), "high", envLevel(2), false},
+		{"invalid env value", new(envLevel), "medium", envLevel(0), true},
+		{"env unmarshaler pointer", new(*envLevel), "low", func() *envLevel { l := envLevel(1); return &l }(), false},
+		{"invalid env value pointer", new(*envLevel), "medium", (*envLevel)(nil), true},
+		{"env unmarshaler elements", new([]envLevel), "low,high", []envLevel{1, 2}, false},
+		{"bytes", new([]byte), "a,b", []byte("a,b"), false},
+		{"strings", new([]string), "a,b,c", []string{"a", "b", "c"}, false},
+		{"empty strings", new([]string), "", []string{}, false},
+		{"signed ints", new([]int), "-1,2,-3", []int{-1, 2, -3}, false},
+		{"floats with exponents", new([]float64), "1e-5,-2.5E+3,+4", []float64{1e-5, -2.5e3, 4}, false},
+		{"invalid element", new([]int), "1,x", []int(nil), true},
+		{"string pointer", new(*string), "abc", strPtr("abc"), false},
+		{"int pointer", new(*int), "0", func() *int { i := 0; return &i }(), false},
+		{"bool pointer", new(*bool), "true", func() *bool { b := true; return &b }(), false},
+		{"float pointer", new(*float64), "1.5", func() *float64 { f := 1.5; return &f }(), false},
+		{"duration pointer", new(*time.Duration), "1s", func() *time.Duration { d := time.Second; return &d }(), false},
+		{"invalid int pointer", new(*int), "abc", (*int)(nil), true},
+		{"struct pointer", new(*struct{ A int }), "abc", (*struct{ A int })(nil), false},
+		{"string map", new(map[string]string), "env=prod,team=core", map[string]string{"env": "prod", "team": "core"}, false},
+		{"int map", new(map[string]int), "a=1,b=-2", map[string]int{"a": 1, "b": -2}, false},
+		{"bool map", new(map[string]bool), "a=true,b=0", map[string]bool{"a": true, "b": false}, false},
+		{"int keys", new(map[int]time.Duration), "1=1s", map[int]time.Duration{1: time.Second}, false},
+		{"value containing delimiter", new(map[string]string), "dsn=a=b", map[string]string{"dsn": "a=b"}, false},
+		{"empty map", new(map[string]string), "", map[string]string{}, false},
+		{"pair without delimiter", new(map[string]string), "a=1,b", map[string]string(nil), true},
+		{"invalid map value", new(map[string]int), "a=x", map[string]int(nil), true},
+		{"unsupported map value", new(map[string][]string), "a=x", map[string][]string(nil), true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			f := reflect.ValueOf(tc.target).Elem()
+			if err := setValue(f, tc.val); (err != nil) != tc.wantErr {
+				t.Fatalf("setValue() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got := f.Interface(); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("setValue() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_SetUnsetSentinel(t *testing.T) {
+	var target struct {
+		Host string `yaml:"host" env:"HOST"`
+		Port int    `yaml:"port" env:"PORT"`
+		User string `yaml:"user"`
+	}
+
+	pr := New(&target)
+	pr.SetUnsetSentinel(DefaultUnsetSentinel)
+	pr.FromYAML([]byte("host: example.com\nport: 80\nuser: admin"))
+	pr.FromYAML([]byte("user: __unset__"))
+	pr.FromArgs([]string{"HOST=__unset__", "PORT=__unset__"})
+
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if target.Host != "" || target.Port != 0 || target.User != "" {
+		t.Errorf("Process() target = %+v, want all fields reset", target)
+	}
+}
+
+func TestPrimordius_SetErrorOnUnsettable(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		wantErr bool
+	}{
+		{"skip by default", false, false},
+		{"error if enabled", true, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var target struct {
+				Host string `env:"HOST"`
+				port int    `env:"PORT"`
+			}
+			pr := New(&target)
+			pr.SetErrorOnUnsettable(tc.enabled)
+			pr.FromArgs([]string{"HOST=example.com", "PORT=80"})
+
+			err := pr.Process()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr && !errors.Is(err, ErrFieldNotSettable) {
+				t.Errorf("Process() error = %v, want %v", err, ErrFieldNotSettable)
+			}
+			if target.port != 0 {
+				t.Errorf("unexported field was set to %d", target.port)
+			}
+		})
+	}
+}
+
+func TestPrimordius_SetValueGuards(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxLength     int
+		rejectControl bool
+		env           map[string]string
+		wantErr       bool
+	}{
+		{"disabled", 0, false, map[string]string{"VG_NAME": "line\nbreak"}, false},
+		{"within limit", 8, true, map[string]string{"VG_NAME": "app"}, false},
+		{"too long", 8, false, map[string]string{"VG_NAME": "application"}, true},
+		{"control character", 0, true, map[string]string{"VG_NAME": "app\x1b[2J"}, true},
+		{"newline", 0, true, map[string]string{"VG_NAME": "line\nbreak"}, true},
+		{"wildcard value", 8, false, map[string]string{"VG_LABEL_a": "application"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				if err := os.Setenv(k, v); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Unsetenv(k)
+			}
+			var target struct {
+				Name   string            `env:"NAME"`
+				Labels map[string]string `env:"LABEL_*"`
+			}
+			pr := New(&target)
+			pr.SetValueGuards(tc.maxLength, tc.rejectControl)
+			pr.FromEnv("VG_")
+
+			err := pr.Process()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr && !errors.Is(err, ErrRejectedValue) {
+				t.Errorf("Process() error = %v, want %v", err, ErrRejectedValue)
+			}
+		})
+	}
+}
+
+func Test_splitList(t *testing.T) {
+	tests := []struct {
+		val   string
+		delim string
+		want  []string
+	}{
+		{"-1,2,-3", ",", []string{"-1", "2", "-3"}},
+		{"-1--2-3", "-", []string{"-1", "-2", "3"}},
+		{"1e-5-2E-3--4", "-", []string{"1e-5", "2E-3", "-4"}},
+		{"+1++2", "+", []string{"+1", "+2"}},
+		{"a-b", "-", []string{"a", "b"}},
+		{"", "-", []string{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.delim+" "+tc.val, func(t *testing.T) {
+			if got := splitList(tc.val, tc.delim); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("splitList() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_envSource_ToTarget_wildcard(t *testing.T) {
+	env := map[string]string{
+		"WC_ROUTE_api":  "http://api.local",
+		"WC_ROUTE_web":  "http://web.local",
+		"WC_LIMIT_api":  "10",
+		"WC_ROUTEX":     "ignored",
+		"WC_BAD_LIMITa": "x",
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+
+	var target struct {
+		Routes map[string]string `env:"ROUTE_*"`
+		Limits map[string]int    `env:"LIMIT_*"`
+	}
+	if err := (&envSource{prefix: "WC_"}).ToTarget(&target); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+	wantRoutes := map[string]string{"api": "http://api.local", "web": "http://web.local"}
+	if !reflect.DeepEqual(target.Routes, wantRoutes) {
+		t.Errorf("Routes = %v, want %v", target.Routes, wantRoutes)
+	}
+	if !reflect.DeepEqual(target.Limits, map[string]int{"api": 10}) {
+		t.Errorf("Limits = %v, want map[api:10]", target.Limits)
+	}
+
+	var invalid struct {
+		Limits map[string]int `env:"BAD_LIMIT*"`
+	}
+	if err := (&envSource{prefix: "WC_"}).ToTarget(&invalid); err == nil {
+		t.Error("ToTarget() error = nil, want error for invalid map value")
+	}
+	var notMap struct {
+		Routes string `env:"ROUTE_*"`
+	}
+	if err := (&envSource{prefix: "WC_"}).ToTarget(&notMap); err == nil {
+		t.Error("ToTarget() error = nil, want error for non-map field")
+	}
+}
+
+func Test_envSource_ToTarget_caseInsensitive(t *testing.T) {
+	t.Setenv("eci_host", "lower.local")
+	t.Setenv("ECI_NAME", "exact")
+	t.Setenv("eci_name", "lower")
+	t.Setenv("Eci_Port", "8080")
+
+	type target struct {
+		Host string `env:"HOST"`
+		Name string `env:"NAME"`
+		Port int    `env:"PORT"`
+	}
+
+	tests := []struct {
+		name string
+		opts EnvOptions
+		want target
+	}{
+		{"case-sensitive", EnvOptions{}, target{Name: "exact"}},
+		{"case-insensitive", EnvOptions{CaseInsensitive: true}, target{Host: "lower.local", Name: "exact", Port: 8080}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&envSource{prefix: "ECI", opts: tc.opts}).ToTarget(&got); err != nil {
+				t.Fatalf("ToTarget() error = %v", err)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_envSource_ToTarget_options(t *testing.T) {
+	type target struct {
+		Host   string            `env:"HOST,required"`
+		Paths  []string          `env:"PATHS"`
+		Ports  []int             `env:"PORTS,delimiter=;"`
+		Labels map[string]string `env:"LABELS"`
+		Name   string            `env:"NAME" required:"true"`
+		Key    []byte            `env:"KEY"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		opts    EnvOptions
+		want    target
+		wantErr error
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"EO_HOST": " example.com ", "EO_PATHS": "a,b", "EO_NAME": "app"},
+			want: target{Host: " example.com ", Paths: []string{"a", "b"}, Name: "app"},
+		},
+		{
+			name: "delimiter and trimming",
+			env:  map[string]string{"EO_HOST": " example.com\n", "EO_PATHS": "/bin:/usr/bin", "EO_PORTS": "80;443", "EO_LABELS": "a=1:b=2"},
+			opts: EnvOptions{Delimiter: ":", TrimSpace: true},
+			want: target{Host: "example.com", Paths: []string{"/bin", "/usr/bin"}, Ports: []int{80, 443}, Labels: map[string]string{"a": "1", "b": "2"}},
+		},
+		{
+			name: "delimiter and bytes",
+			env:  map[string]string{"EO_HOST": "example.com", "EO_PATHS": "a:b", "EO_KEY": "secret:1"},
+			opts: EnvOptions{Delimiter: ":"},
+			want: target{Host: "example.com", Paths: []string{"a", "b"}, Key: []byte("secret:1")},
+		},
+		{
+			name: "missing optional variable",
+			env:  map[string]string{"EO_HOST": "example.com", "EO_NAME": "app"},
+			opts: EnvOptions{ErrorOnMissing: true},
+			want: target{Host: "example.com", Name: "app"},
+		},
+		{
+			name:    "missing required variable",
+			env:     map[string]string{"EO_HOST": "example.com"},
+			opts:    EnvOptions{ErrorOnMissing: true},
+			wantErr: ErrMissingRequired,
+		},
+		{
+			name:    "missing required variable by option",
+			env:     map[string]string{"EO_NAME": "app"},
+			opts:    EnvOptions{ErrorOnMissing: true},
+			wantErr: ErrMissingRequired,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			var got target
+			err := (&envSource{prefix: "EO", opts: tc.opts}).ToTarget(&got)
+			if !errors.Is(err, tc.wantErr) || (tc.wantErr == nil && err != nil) {
+				t.Fatalf("ToTarget() error = %v, want %v", err, tc.wantErr)
+			}
+			if tc.wantErr == nil && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_envSource_ToTarget_presence(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		want bool
+	}{
+		{name: "set", env: map[string]string{"PR_VERBOSE": "1"}, want: true},
+		{name: "set to false", env: map[string]string{"PR_VERBOSE": "false"}, want: true},
+		{name: "set empty", env: map[string]string{"PR_VERBOSE": ""}, want: true},
+		{name: "unset", env: map[string]string{}, want: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				if err := os.Setenv(k, v); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Unsetenv(k)
+			}
+			var target struct {
+				Verbose bool `env:"VERBOSE,presence"`
+			}
+			if err := (&envSource{prefix: "PR_"}).ToTarget(&target); err != nil {
+				t.Fatalf("ToTarget() error = %v", err)
+			}
+			if target.Verbose != tc.want {
+				t.Errorf("Verbose = %v, want %v", target.Verbose, tc.want)
+			}
+		})
+	}
+
+	if err := os.Setenv("PR_LEVEL", "1"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("PR_LEVEL")
+	var invalid struct {
+		Level int `env:"LEVEL,presence"`
+	}
+	if err := (&envSource{prefix: "PR_"}).ToTarget(&invalid); err == nil {
+		t.Error("ToTarget() error = nil, want error for non-bool field")
+	}
+}
+
+func Test_envSource_ToTarget_base64(t *testing.T) {
+	type target struct {
+		Key []byte `env:"KEY,base64"`
+		Raw []byte `env:"RAW"`
+	}
+	type invalid struct {
+		Key string `env:"KEY,base64"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		target  any
+		want    any
+		wantErr bool
+	}{
+		{
+			name:   "decoded",
+			env:    map[string]string{"B64_KEY": "AAEC/w==", "B64_RAW": "AAEC/w=="},
+			target: &target{},
+			want:   &target{Key: []byte{0, 1, 2, 255}, Raw: []byte("AAEC/w==")},
+		},
+		{
+			name:   "empty",
+			env:    map[string]string{"B64_KEY": ""},
+			target: &target{},
+			want:   &target{Key: []byte{}},
+		},
+		{
+			name:    "invalid base64",
+			env:     map[string]string{"B64_KEY": "AAEC/w="},
+			target:  &target{},
+			wantErr: true,
+		},
+		{
+			name:    "no byte slice",
+			env:     map[string]string{"B64_KEY": "AAEC/w=="},
+			target:  &invalid{},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			err := (&envSource{prefix: "B64"}).ToTarget(tc.target)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(tc.target, tc.want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", tc.target, tc.want)
+			}
+		})
+	}
+}
+
+type testNode struct {
+	Name string `env:"NAME"`
+	Next *testNode
+}
+
+func Test_envSource_ToTarget_pointers(t *testing.T) {
+	type target struct {
+		Port    *int     `env:"PORT"`
+		Name    *string  `env:"NAME"`
+		Debug   *bool    `env:"DEBUG"`
+		Ratio   *float64 `env:"RATIO"`
+		Missing *int     `env:"MISSING"`
+	}
+	env := map[string]string{"PP_PORT": "0", "PP_NAME": "", "PP_DEBUG": "false", "PP_RATIO": "0.5"}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+
+	var got target
+	if err := (&envSource{prefix: "PP_"}).ToTarget(&got); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+	if got.Port == nil || *got.Port != 0 || got.Name == nil || *got.Name != "" || got.Debug == nil || *got.Debug ||
+		got.Ratio == nil || *got.Ratio != 0.5 || got.Missing != nil {
+		t.Errorf("ToTarget() got = %+v", got)
+	}
+}
+
+func Test_envSource_ToTarget_maps(t *testing.T) {
+	t.Setenv("PM_LABELS", "env=prod,team=core")
+	t.Setenv("PM_LIMITS", "cpu:2;memory:512")
+	var got struct {
+		Labels map[string]string `env:"LABELS"`
+		Limits map[string]int    `env:"LIMITS,delimiter=;,kvdelimiter=:"`
+	}
+	if err := (&envSource{prefix: "PM_"}).ToTarget(&got); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+	if want := map[string]string{"env": "prod", "team": "core"}; !reflect.DeepEqual(got.Labels, want) {
+		t.Errorf("Labels = %v, want %v", got.Labels, want)
+	}
+	if want := map[string]int{"cpu": 2, "memory": 512}; !reflect.DeepEqual(got.Limits, want) {
+		t.Errorf("Limits = %v, want %v", got.Limits, want)
+	}
+}
+
+func Test_envSource_ToTarget_sliceDelimiter(t *testing.T) {
+	type target struct {
+		Paths   []string  `env:"PATHS,delimiter=:"`
+		Offsets []int     `env:"OFFSETS,delimiter=-"`
+		Ratios  []float64 `env:"RATIOS,delimiter=;"`
+		Tags    []string  `env:"TAGS"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    target
+		wantErr bool
+	}{
+		{
+			name: "custom delimiters",
+			env:  map[string]string{"PSD_PATHS": "/usr/bin:/bin", "PSD_RATIOS": "0.5;5e-1", "PSD_TAGS": "a,b"},
+			want: target{Paths: []string{"/usr/bin", "/bin"}, Ratios: []float64{0.5, 0.5}, Tags: []string{"a", "b"}},
+		},
+		{
+			name: "commas within elements",
+			env:  map[string]string{"PSD_PATHS": "a,b:c"},
+			want: target{Paths: []string{"a,b", "c"}},
+		},
+		{
+			name: "signed elements",
+			env:  map[string]string{"PSD_OFFSETS": "-1--2-3"},
+			want: target{Offsets: []int{-1, -2, 3}},
+		},
+		{
+			name: "empty value",
+			env:  map[string]string{"PSD_PATHS": ""},
+			want: target{Paths: []string{}},
+		},
+		{
+			name:    "invalid element",
+			env:     map[string]string{"PSD_RATIOS": "0.5;x"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			var got target
+			err := (&envSource{prefix: "PSD_"}).ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_envSource_ToTarget_timeLayout(t *testing.T) {
+	type target struct {
+		Start    time.Time  `env:"START,layout=2006-01-02"`
+		Deadline *time.Time `env:"DEADLINE,layout=02.01.2006 15:04"`
+		Created  time.Time  `env:"CREATED"`
+	}
+	day := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	deadline := time.Date(2024, 12, 24, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		want    target
+		wantErr bool
+	}{
+		{
+			name: "layouts",
+			env:  map[string]string{"PTL_START": "2024-03-01", "PTL_DEADLINE": "24.12.2024 18:30", "PTL_CREATED": "2024-03-01T00:00:00Z"},
+			want: target{Start: day, Deadline: &deadline, Created: day},
+		},
+		{
+			name:    "value not matching layout",
+			env:     map[string]string{"PTL_START": "2024-03-01T00:00:00Z"},
+			wantErr: true,
+		},
+		{
+			name:    "RFC 3339 without layout",
+			env:     map[string]string{"PTL_CREATED": "01.03.2024"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			var got target
+			err := (&envSource{prefix: "PTL_"}).ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_converter_applyTagged_nested(t *testing.T) {
+	type database struct {
+		Host string `env:"DB_HOST"`
+	}
+	type target struct {
+		DB      database
+		Replica *database
+		Missing *database
+		Skipped database `env:"-"`
+	}
+
+	var got target
+	got.Replica = &database{}
+	kv := valueMap{"DB_HOST": "db.local"}
+	if err := (&converter{}).applyTagged(&got, kv); err != nil {
+		t.Fatalf("applyTagged() error = %v", err)
+	}
+	if got.DB.Host != "db.local" || got.Replica.Host != "db.local" {
+		t.Errorf("applyTagged() got = %+v, %+v, want nested fields set", got.DB, got.Replica)
+	}
+	if got.Missing != nil || got.Skipped.Host != "" {
+		t.Errorf("applyTagged() got = %+v, %+v, want untouched fields", got.Missing, got.Skipped)
+	}
+
+	tests := []struct {
+		name    string
+		node    func() *testNode
+		depth   int
+		wantErr error
+	}{
+		{"chain within limit", func() *testNode { return &testNode{Next: &testNode{Next: &testNode{}}} }, 2, nil},
+		{"chain exceeding limit", func() *testNode { return &testNode{Next: &testNode{Next: &testNode{}}} }, 1, ErrMaxDepth},
+		{"cycle", func() *testNode {
+			a, b := &testNode{}, &testNode{}
+			a.Next, b.Next = b, a
+			return a
+		}, 0, ErrPointerCycle},
+		{"self reference", func() *testNode {
+			a := &testNode{}
+			a.Next = a
+			return a
+		}, 0, ErrPointerCycle},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			node := tc.node()
+			err := (&converter{depth: tc.depth}).applyTagged(node, valueMap{"NAME": "n"})
+			if !errors.Is(err, tc.wantErr) {
+				t.Fatalf("applyTagged() error = %v, want %v", err, tc.wantErr)
+			}
+			if err == nil && node.Next.Next.Name != "n" {
+				t.Errorf("applyTagged() did not set nested field")
+			}
+		})
+	}
+}
+
+func TestPrimordius_Process_pointerCycle(t *testing.T) {
+	type target struct {
+		Name  string `env:"NAME"`
+		Nodes []*testNode
+		Head  *testNode
+	}
+	tests := []struct {
+		name string
+		run  func(pr *Primordius) error
+	}{
+		{"Process", (*Primordius).Process},
+		{"ProcessFields", func(pr *Primordius) error { return pr.ProcessFields("Name") }},
+		{"ProcessReport", func(pr *Primordius) error { _, err := pr.ProcessReport(); return err }},
+		{"Reload", func(pr *Primordius) error { _, err := pr.Reload(); return err }},
+	}
+
+	for _, tc := range tests {
+		for _, cycle := range []string{"pointer", "slice"} {
+			t.Run(tc.name+"/"+cycle, func(t *testing.T) {
+				node := &testNode{}
+				node.Next = node
+				var got target
+				if cycle == "pointer" {
+					got.Head = node
+				} else {
+					got.Nodes = []*testNode{node}
+				}
+				pr := New(&got)
+				pr.FromJSON([]byte(`{"Name": "app"}`))
+				if err := tc.run(pr); !errors.Is(err, ErrPointerCycle) {
+					t.Fatalf("%s() error = %v, want %v", tc.name, err, ErrPointerCycle)
+				}
+			})
+		}
+	}
+}
+
+func Test_walkers_pointerCycle(t *testing.T) {
+	node := &testNode{Name: "A"}
+	node.Next = node
+	target := struct {
+		Head *testNode `env:"HEAD,lower"`
+	}{Head: node}
+	v := reflect.ValueOf(&target)
+
+	if err := coerceCases(v, "", make(map[visit]bool)); !errors.Is(err, ErrPointerCycle) {
+		t.Errorf("coerceCases() error = %v, want %v", err, ErrPointerCycle)
+	}
+	err := walkStrings(v, "", make(map[visit]bool), func(_, val string) (string, error) { return val, nil })
+	if !errors.Is(err, ErrPointerCycle) {
+		t.Errorf("walkStrings() error = %v, want %v", err, ErrPointerCycle)
+	}
+	if _, err := deepCopy(v); !errors.Is(err, ErrPointerCycle) {
+		t.Errorf("deepCopy() error = %v, want %v", err, ErrPointerCycle)
+	}
+	if _, err := takeSnapshot(&target); !errors.Is(err, ErrPointerCycle) {
+		t.Errorf("takeSnapshot() error = %v, want %v", err, ErrPointerCycle)
+	}
+	if err := NoOverwrite(&envSource{prefix: "CYCLE"}).ToTarget(&target); !errors.Is(err, ErrPointerCycle) {
+		t.Errorf("NoOverwrite().ToTarget() error = %v, want %v", err, ErrPointerCycle)
+	}
+
+	// shared pointers without a cycle are fine
+	shared := &testNode{Name: "B"}
+	diamond := struct{ A, B *testNode }{shared, shared}
+	if _, err := takeSnapshot(&diamond); err != nil {
+		t.Errorf("takeSnapshot() error = %v for shared pointers", err)
+	}
+}
+
+func Test_envSource_ToTarget_slices(t *testing.T) {
+	env := map[string]string{
+		"SL_HOSTS":  "a,b,c",
+		"SL_PORTS":  "80,443",
+		"SL_RATIOS": "0.5,1e3",
+		"SL_FLAGS":  "true,false,1",
+		"SL_RAW":    "a,b",
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+
+	var target struct {
+		Hosts  []string  `env:"HOSTS"`
+		Ports  []int     `env:"PORTS"`
+		Ratios []float64 `env:"RATIOS"`
+		Flags  []bool    `env:"FLAGS"`
+		Raw    []byte    `env:"RAW"`
+	}
+	if err := (&envSource{prefix: "SL_"}).ToTarget(&target); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+	if !reflect.DeepEqual(target.Hosts, []string{"a", "b", "c"}) ||
+		!reflect.DeepEqual(target.Ports, []int{80, 443}) ||
+		!reflect.DeepEqual(target.Ratios, []float64{0.5, 1000}) ||
+		!reflect.DeepEqual(target.Flags, []bool{true, false, true}) ||
+		string(target.Raw) != "a,b" {
+		t.Errorf("ToTarget() got = %+v", target)
+	}
+
+	if err := os.Setenv("SL_PORTS", "80,https"); err != nil {
+		t.Fatal(err)
+	}
+	err := (&envSource{prefix: "SL_"}).ToTarget(&target)
+	if err == nil || !strings.Contains(err.Error(), "field Ports") || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("ToTarget() error = %v, want error naming field and element", err)
+	}
+	var numErr *strconv.NumError
+	if !errors.As(err, &numErr) {
+		t.Errorf("ToTarget() error = %v, want wrapped *strconv.NumError", err)
+	}
+}
+
+func TestPrimordius_Warnings(t *testing.T) {
+	tests := []struct {
+		name         string
+		bestEffort   bool
+		args         []string
+		wantErr      bool
+		wantWarnings []string
+		wantPorts    []int
+	}{
+		{"valid", true, []string{"PORTS=80,443"}, false, nil, []int{80, 443}},
+		{"invalid element fails by default", false, []string{"PORTS=80,https"}, true, nil, []int{1}},
+		{"invalid element is a warning in best-effort mode", true, []string{"PORTS=80,https", "LIMITS_a=x"}, false,
+			[]string{"field Ports: element 1", "field Limits: key LIMITS_a"}, []int{1}},
+		{"deprecated key", false, []string{"OLD_PORTS=8080", "TIMEOUT=1"}, false,
+			[]string{"key OLD_PORTS is deprecated, use PORTS instead", "key TIMEOUT is deprecated"}, []int{1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			target := struct {
+				Ports   []int          `env:"PORTS"`
+				Old     []int          `env:"OLD_PORTS,deprecated=PORTS"`
+				Timeout int            `env:"TIMEOUT,deprecated"`
+				Limits  map[string]int `env:"LIMITS_*"`
+			}{Ports: []int{1}}
+			pr := New(&target)
+			pr.SetBestEffort(tc.bestEffort)
+			pr.FromArgs(tc.args)
+
+			if err := pr.Process(); (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(target.Ports, tc.wantPorts) {
+				t.Errorf("Ports = %v, want %v", target.Ports, tc.wantPorts)
+			}
+			warnings := pr.Warnings()
+			if len(warnings) != len(tc.wantWarnings) {
+				t.Fatalf("Warnings() = %v, want %d warnings", warnings, len(tc.wantWarnings))
+			}
+			for i, want := range tc.wantWarnings {
+				if !strings.Contains(warnings[i].Error(), want) {
+					t.Errorf("Warnings()[%d] = %v, want it to contain %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestUpperSnakeCase(t *testing.T) {
+	tests := []struct {
+		path []string
+		want string
+	}{
+		{[]string{"Port"}, "PORT"},
+		{[]string{"Database", "MaxConns"}, "DATABASE_MAX_CONNS"},
+		{[]string{"BaseURL"}, "BASE_URL"},
+		{[]string{"HTTPServer", "TLSCertFile"}, "HTTP_SERVER_TLS_CERT_FILE"},
+		{[]string{"Retries3Times"}, "RETRIES3_TIMES"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			if got := UpperSnakeCase(tc.path); got != tc.want {
+				t.Errorf("UpperSnakeCase() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_SetKeyMapper(t *testing.T) {
+	t.Setenv("KM_PORT", "80")
+	t.Setenv("KM_DATABASE_MAX_CONNS", "10")
+	t.Setenv("KM_DB_USER", "admin")
+	t.Setenv("KM_DATABASE_USER", "ignored")
+	t.Setenv("KM_LOG_LEVEL", "DEBUG")
+	type database struct {
+		MaxConns int
+		User     string `env:"DB_USER"`
+		Password string `env:"-"`
+	}
+	type target struct {
+		Port     int
+		Timeout  time.Duration
+		LogLevel string `env:",lower"`
+		BaseURL  string
+		Database database
+		Replica  *database
+	}
+
+	var got target
+	pr := New(&got)
+	pr.SetKeyMapper(UpperSnakeCase)
+	pr.FromEnv("KM")
+	pr.FromFlags([]string{"--timeout=5s", "--base-url=http://localhost"})
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	want := target{Port: 80, Timeout: 5 * time.Second, LogLevel: "debug", BaseURL: "http://localhost",
+		Database: database{MaxConns: 10, User: "admin"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Process() got = %+v, want %+v", got, want)
+	}
+}
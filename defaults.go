@@ -0,0 +1,166 @@
+package primordius
+
+import (
+	"fmt"
+	"reflect"
+)
+
+const defaultTagName = "default"
+
+type (
+	defaultsSource struct {
+		conv *converter
+	}
+	structSource struct {
+		defaults any
+	}
+)
+
+func (ds *defaultsSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	return ds.conv.applyDefaults(v.Elem(), "", 0, make(map[visit]bool))
+}
+
+// applyDefaults sets all zero fields of the struct s carrying a non-empty default tag
+// to the value of the tag, descending into nested structs.
+func (c *converter) applyDefaults(s reflect.Value, path string, depth int, visited map[visit]bool) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := s.Field(i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		def, ok := sf.Tag.Lookup(defaultTagName)
+		if !ok {
+			if err := c.applyNestedDefaults(f, fieldPath, depth, visited); err != nil {
+				return err
+			}
+			continue
+		}
+		if def == "" || !isZero(f) {
+			continue
+		}
+		if err := setValue(f, def); err != nil {
+			if err := c.convertError(fmt.Errorf("field %s: default: %w", fieldPath, err)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// applyNestedDefaults descends into f if it is, or points to, a struct.
+func (c *converter) applyNestedDefaults(f reflect.Value, path string, depth int, visited map[visit]bool) error {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() || !hasExportedFields(f.Type().Elem()) {
+			return nil
+		}
+		v := visit{typ: f.Type(), ptr: f.Pointer()}
+		if visited[v] {
+			return fmt.Errorf("%w: field %s", ErrPointerCycle, path)
+		}
+		visited[v] = true
+		defer delete(visited, v)
+		f = f.Elem()
+	}
+	if !hasExportedFields(f.Type()) {
+		return nil
+	}
+	if depth+1 > c.maxDepth() {
+		return fmt.Errorf("%w: field %s is nested more than %d levels deep", ErrMaxDepth, path, c.maxDepth())
+	}
+
+	return c.applyDefaults(f, path, depth+1, visited)
+}
+
+// WithDefaults adds a Source to pr which sets fields to the value of their default tag,
+// e.g. `env:"PORT" default:"8080"`, converted according to the type of the field just
+// like values from env vars. Fields which are not zero at processing time, e.g. because
+// an earlier source set them, are left unchanged, as are fields with an empty default tag.
+// Call WithDefaults before adding any other source so the defaults can be overridden.
+func (pr *Primordius) WithDefaults() {
+	pr.AddSource(&defaultsSource{conv: pr.conv})
+}
+
+func (ss *structSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+	d := reflect.ValueOf(ss.defaults)
+	if d.Kind() == reflect.Pointer && !d.IsNil() {
+		d = d.Elem()
+	}
+	if d.Kind() != reflect.Struct {
+		return fmt.Errorf("%w: defaults must be a struct or a pointer to one", ErrInvalidSpecification)
+	}
+
+	return fillByName(v.Elem(), d, "")
+}
+
+// fillByName sets all zero fields of the struct dst to copies of the non-zero fields
+// of the struct src with the same name, descending into nested structs.
+func fillByName(dst, src reflect.Value, path string) error {
+	t := dst.Type()
+	for i := 0; i < dst.NumField(); i++ {
+		df := t.Field(i)
+		if !df.IsExported() {
+			continue
+		}
+		sf, ok := src.Type().FieldByName(df.Name)
+		if !ok || !sf.IsExported() || len(sf.Index) != 1 {
+			continue
+		}
+		fieldPath := df.Name
+		if path != "" {
+			fieldPath = path + "." + df.Name
+		}
+
+		dv, sv := dst.Field(i), src.Field(sf.Index[0])
+		if hasExportedFields(df.Type) && hasExportedFields(sf.Type) &&
+			!df.Type.Implements(zeroerType) && !reflect.PointerTo(df.Type).Implements(zeroerType) {
+			if err := fillByName(dv, sv, fieldPath); err != nil {
+				return err
+			}
+			continue
+		}
+		if isZero(sv) || !isZero(dv) {
+			continue
+		}
+		if !sf.Type.AssignableTo(df.Type) {
+			return fmt.Errorf("field %s: cannot use default of type %s as %s", fieldPath, sf.Type, df.Type)
+		}
+		c, err := copyValue(sv, fieldPath, make(map[visit]bool))
+		if err != nil {
+			return err
+		}
+		dv.Set(c)
+	}
+
+	return nil
+}
+
+// FromStruct adds a Source to pr which copies the non-zero fields of defaults, a struct
+// or a pointer to one, into the fields of the target with the same name, e.g.
+//
+//	pr.FromStruct(Config{Port: 8080, Database: Database{Host: "localhost"}})
+//
+// Nested structs are filled field by field. Like with WithDefaults, only fields which
+// are zero at processing time are set, so call FromStruct before adding other sources.
+// defaults may be of a different type than the target; fields of the same name must be
+// of assignable types, though. Values are copied, so changing defaults later has no effect
+// on the target.
+func (pr *Primordius) FromStruct(defaults any) {
+	pr.AddSource(&structSource{defaults: defaults})
+}
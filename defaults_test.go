@@ -0,0 +1,164 @@
+package primordius
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPrimordius_WithDefaults(t *testing.T) {
+	type database struct {
+		Host    string        `default:"localhost"`
+		Timeout time.Duration `default:"5s"`
+	}
+	type target struct {
+		Port     int      `env:"PORT" default:"8080"`
+		Name     string   `env:"NAME" default:""`
+		Tags     []string `default:"a,b"`
+		Debug    bool     `default:"true"`
+		Database database
+		Replica  *database
+	}
+
+	tests := []struct {
+		name    string
+		initial target
+		env     map[string]string
+		want    target
+	}{
+		{
+			name: "defaults only",
+			want: target{Port: 8080, Tags: []string{"a", "b"}, Debug: true, Database: database{Host: "localhost", Timeout: 5 * time.Second}},
+		},
+		{
+			name:    "values set before are kept",
+			initial: target{Port: 9000, Database: database{Host: "db"}, Replica: &database{Timeout: time.Second}},
+			want: target{Port: 9000, Tags: []string{"a", "b"}, Debug: true, Database: database{Host: "db", Timeout: 5 * time.Second},
+				Replica: &database{Host: "localhost", Timeout: time.Second}},
+		},
+		{
+			name: "later sources override",
+			env:  map[string]string{"APP_PORT": "1234", "APP_NAME": "svc"},
+			want: target{Port: 1234, Name: "svc", Tags: []string{"a", "b"}, Debug: true, Database: database{Host: "localhost", Timeout: 5 * time.Second}},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			got := tc.initial
+			pr := New(&got)
+			pr.WithDefaults()
+			pr.FromEnv("APP")
+			if err := pr.Process(); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_WithDefaults_invalid(t *testing.T) {
+	target := struct {
+		Port int `default:"eighty"`
+	}{}
+	pr := New(&target)
+	pr.WithDefaults()
+	if err := pr.Process(); err == nil {
+		t.Error("Process() error = nil, want error for invalid default")
+	}
+}
+
+func TestPrimordius_FromStruct(t *testing.T) {
+	type database struct {
+		Host    string
+		Timeout time.Duration
+	}
+	type target struct {
+		Port     int    `env:"PORT"`
+		Name     string `env:"NAME"`
+		Tags     []string
+		Database database
+		Replica  *database
+		internal int
+	}
+	type partial struct {
+		Name    string
+		Tags    []string
+		Unknown bool
+	}
+
+	tags := []string{"a", "b"}
+	tests := []struct {
+		name     string
+		initial  target
+		defaults any
+		env      map[string]string
+		want     target
+		wantErr  bool
+	}{
+		{
+			name:     "defaults only",
+			defaults: target{Port: 8080, Tags: tags, Database: database{Host: "localhost"}, Replica: &database{Host: "replica"}, internal: 1},
+			want:     target{Port: 8080, Tags: []string{"a", "b"}, Database: database{Host: "localhost"}, Replica: &database{Host: "replica"}},
+		},
+		{
+			name:     "values set before are kept",
+			initial:  target{Port: 9000, Database: database{Timeout: time.Second}},
+			defaults: &target{Port: 8080, Database: database{Host: "localhost", Timeout: 5 * time.Second}},
+			want:     target{Port: 9000, Database: database{Host: "localhost", Timeout: time.Second}},
+		},
+		{
+			name:     "later sources override",
+			defaults: target{Port: 8080, Name: "default"},
+			env:      map[string]string{"APP_PORT": "1234"},
+			want:     target{Port: 1234, Name: "default"},
+		},
+		{
+			name:     "other type",
+			defaults: partial{Name: "svc", Tags: tags, Unknown: true},
+			want:     target{Name: "svc", Tags: []string{"a", "b"}},
+		},
+		{
+			name:     "mismatching field type",
+			defaults: struct{ Port string }{Port: "8080"},
+			wantErr:  true,
+		},
+		{
+			name:     "no struct",
+			defaults: map[string]any{"Port": 8080},
+			wantErr:  true,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			got := tc.initial
+			pr := New(&got)
+			pr.FromStruct(tc.defaults)
+			pr.FromEnv("APP")
+			if err := pr.Process(); (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+			if got.Tags != nil {
+				got.Tags[0] = "changed"
+				if tags[0] != "a" {
+					t.Error("Process() shares slices with the defaults")
+				}
+			}
+		})
+	}
+}
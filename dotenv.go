@@ -0,0 +1,123 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type dotEnvFileSource struct {
+	name   string
+	prefix string
+	conv   *converter
+}
+
+func (ds *dotEnvFileSource) ToTarget(t any) error {
+	cont, err := os.ReadFile(ds.name)
+	if err != nil {
+		return err
+	}
+	vars, err := parseDotEnv(string(stripBOM(cont)), ds.conv != nil && ds.conv.strictDotEnv)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ds.name, err)
+	}
+
+	prefix := ds.conv.envPrefix(ds.prefix)
+	values := make(valueMap, len(vars))
+	for name, val := range vars {
+		if key := strings.TrimPrefix(name, prefix); key != name || prefix == "" {
+			values[key] = val
+		}
+	}
+
+	return ds.conv.applyTagged(t, values)
+}
+
+func (ds *dotEnvFileSource) describe() string {
+	return ds.name
+}
+
+func (ds *dotEnvFileSource) modTime() time.Time { return fileModTime(ds.name) }
+
+// parseDotEnv parses the KEY=VALUE lines of a dotenv file. Blank lines, comments
+// starting with # and an "export " prefix are ignored. Values can be double-quoted
+// using Go escape sequences or single-quoted to be taken literally; unquoted values
+// end at a # preceded by whitespace. Malformed lines are skipped unless strict is true.
+func parseDotEnv(content string, strict bool) (map[string]string, error) {
+	vars := make(map[string]string)
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimSpace(strings.TrimPrefix(line, "export "))
+		key, val, ok := strings.Cut(line, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" || strings.ContainsAny(key, " \t") {
+			if strict {
+				return nil, fmt.Errorf("line %d: expected KEY=VALUE", i+1)
+			}
+			continue
+		}
+
+		val, err := dotEnvValue(strings.TrimSpace(val))
+		if err != nil {
+			if strict {
+				return nil, fmt.Errorf("line %d: value of key %s: %w", i+1, key, err)
+			}
+			continue
+		}
+		vars[key] = val
+	}
+
+	return vars, nil
+}
+
+// dotEnvValue returns the value of a dotenv line with quotes and comments removed.
+func dotEnvValue(val string) (string, error) {
+	switch {
+	case strings.HasPrefix(val, `"`):
+		quoted, err := strconv.QuotedPrefix(val)
+		if err != nil {
+			return "", err
+		}
+		return strconv.Unquote(quoted)
+	case strings.HasPrefix(val, "'"):
+		end := strings.Index(val[1:], "'")
+		if end == -1 {
+			return "", errors.New("missing closing quote")
+		}
+		return val[1 : end+1], nil
+	}
+	if i := strings.Index(val, " #"); i != -1 {
+		val = val[:i]
+	}
+	if i := strings.Index(val, "\t#"); i != -1 {
+		val = val[:i]
+	}
+	return strings.TrimSpace(val), nil
+}
+
+// SetStrictDotEnv controls whether malformed lines of dotenv files, e.g. lines without
+// an equals sign, make processing fail instead of being skipped.
+func (pr *Primordius) SetStrictDotEnv(strict bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.strictDotEnv = strict
+}
+
+// FromDotEnvFile adds a Source to pr which reads the KEY=VALUE lines of the dotenv file
+// name, e.g. ".env", and assigns the values to the fields by their env tags just like
+// FromEnv, but without touching the environment.
+func (pr *Primordius) FromDotEnvFile(name string) {
+	pr.FromDotEnvFileWithPrefix(name, "")
+}
+
+// FromDotEnvFileWithPrefix works like FromDotEnvFile, but only considers keys starting
+// with prefix, which is separated from the keys of the env tags like with FromEnv.
+func (pr *Primordius) FromDotEnvFileWithPrefix(name, prefix string) {
+	pr.AddSource(&dotEnvFileSource{name: name, prefix: prefix, conv: pr.conv})
+}
@@ -0,0 +1,102 @@
+package primordius
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_parseDotEnv(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		strict  bool
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "plain, comments and blank lines",
+			content: "# database\nHOST=db.local\n\nPORT = 5432 # default port\r\n",
+			want:    map[string]string{"HOST": "db.local", "PORT": "5432"},
+		},
+		{
+			name:    "export prefix",
+			content: "export TOKEN=abc",
+			want:    map[string]string{"TOKEN": "abc"},
+		},
+		{
+			name:    "quoted values",
+			content: "GREETING=\"hello # world\\n\"\nRAW='a\\nb # c'\nEMPTY=",
+			want:    map[string]string{"GREETING": "hello # world\n", "RAW": `a\nb # c`, "EMPTY": ""},
+		},
+		{
+			name:    "value containing equals sign",
+			content: "DSN=postgres://u@h/db?sslmode=disable",
+			want:    map[string]string{"DSN": "postgres://u@h/db?sslmode=disable"},
+		},
+		{
+			name:    "malformed lines skipped",
+			content: "JUNK\n=value\nRAW='open\nOK=1",
+			want:    map[string]string{"OK": "1"},
+		},
+		{"malformed line in strict mode", "OK=1\nJUNK", true, nil, true},
+		{"unterminated quote in strict mode", `A="open`, true, nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseDotEnv(tc.content, tc.strict)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseDotEnv() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseDotEnv() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromDotEnvFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), ".env")
+	content := "APP_PORT=8080\nAPP_LABEL_TEAM=core\nPORT=1\nexport APP_DEBUG=true\n"
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	type target struct {
+		Port   int               `env:"PORT"`
+		Debug  bool              `env:"DEBUG"`
+		Labels map[string]string `env:"LABEL_*"`
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		want   target
+	}{
+		{"with prefix", "APP", target{Port: 8080, Debug: true, Labels: map[string]string{"TEAM": "core"}}},
+		{"without prefix", "", target{Port: 1}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.FromDotEnvFileWithPrefix(name, tc.prefix)
+			if err := pr.Process(); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+
+	var got target
+	pr := New(&got)
+	pr.SetStrictDotEnv(true)
+	pr.FromDotEnvFile(filepath.Join(t.TempDir(), "missing.env"))
+	if err := pr.Process(); err == nil {
+		t.Error("Process() error = nil, want error for missing file")
+	}
+}
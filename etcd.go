@@ -0,0 +1,72 @@
+package primordius
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// DefaultEtcdTimeout is the default time an etcd source may take to fetch its keys.
+const DefaultEtcdTimeout = 5 * time.Second
+
+type (
+	// EtcdKV is the subset of an etcd client required by the etcd source. Wrap your
+	// client to satisfy it, e.g. for go.etcd.io/etcd/client/v3:
+	//
+	//	func (a adapter) GetPrefix(ctx context.Context, prefix string) (map[string][]byte, error) {
+	//		resp, err := a.c.Get(ctx, prefix, clientv3.WithPrefix())
+	//		if err != nil {
+	//			return nil, err
+	//		}
+	//		entries := make(map[string][]byte, len(resp.Kvs))
+	//		for _, kv := range resp.Kvs {
+	//			entries[string(kv.Key)] = kv.Value
+	//		}
+	//		return entries, nil
+	//	}
+	EtcdKV interface {
+		// GetPrefix returns the values of all keys starting with prefix, by key.
+		GetPrefix(ctx context.Context, prefix string) (map[string][]byte, error)
+	}
+	etcdSource struct {
+		kv     EtcdKV
+		prefix string
+		conv   *converter
+	}
+)
+
+func (es *etcdSource) ToTarget(t any) error {
+	timeout := es.conv.etcdTimeout
+	if timeout <= 0 {
+		timeout = DefaultEtcdTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	entries, err := es.kv.GetPrefix(ctx, es.prefix)
+	if err != nil {
+		return fmt.Errorf("reading etcd keys under %s: %w", es.prefix, err)
+	}
+
+	return es.conv.applyTagged(t, prefixedValues(entries, es.prefix))
+}
+
+func (es *etcdSource) describe() string {
+	return es.prefix
+}
+
+// SetEtcdTimeout sets the time each etcd source may take to fetch its keys. A
+// non-positive timeout selects DefaultEtcdTimeout.
+func (pr *Primordius) SetEtcdTimeout(timeout time.Duration) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.etcdTimeout = timeout
+}
+
+// FromEtcd adds a Source to pr which reads all keys under prefix from etcd, e.g.
+// "/my-app/" for "/my-app/port". The keys are matched against the 'env' tag without
+// the prefix, e.g. `env:"port"`, and values are converted like those of environment
+// variables. The keys are fetched once per call to Process; see SetEtcdTimeout.
+func (pr *Primordius) FromEtcd(kv EtcdKV, prefix string) {
+	pr.AddSource(&etcdSource{kv: kv, prefix: prefix, conv: pr.conv})
+}
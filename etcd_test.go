@@ -0,0 +1,74 @@
+package primordius
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+type fakeEtcdKV struct {
+	entries map[string]string
+	delay   time.Duration
+}
+
+func (fe fakeEtcdKV) GetPrefix(ctx context.Context, prefix string) (map[string][]byte, error) {
+	select {
+	case <-time.After(fe.delay):
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	entries := make(map[string][]byte)
+	for key, val := range fe.entries {
+		if strings.HasPrefix(key, prefix) {
+			entries[key] = []byte(val)
+		}
+	}
+	return entries, nil
+}
+
+func Test_etcdSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host    string        `env:"host"`
+		Timeout time.Duration `env:"http/timeout"`
+	}
+
+	entries := map[string]string{
+		"/app/host":             "example.com",
+		"/app/http/timeout":     "5s",
+		"/other/host":           "ignored",
+		"/invalid/http/timeout": "soon",
+	}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		delay   time.Duration
+		timeout time.Duration
+		want    target
+		wantErr bool
+	}{
+		{"prefix", "/app/", 0, 0, target{"example.com", 5 * time.Second}, false},
+		{"nothing under prefix", "/missing/", 0, 0, target{}, false},
+		{"invalid value", "/invalid/", 0, 0, target{}, true},
+		{"within timeout", "/app", 10 * time.Millisecond, time.Second, target{"example.com", 5 * time.Second}, false},
+		{"timeout", "/app/", time.Second, 10 * time.Millisecond, target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			es := &etcdSource{
+				kv:     fakeEtcdKV{entries: entries, delay: tc.delay},
+				prefix: tc.prefix,
+				conv:   &converter{etcdTimeout: tc.timeout},
+			}
+			if err := es.ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+package primordius_test
+
+import (
+	"fmt"
+	"log"
+	"testing/fstest"
+
+	"github.com/KaiserWerk/primordius"
+)
+
+// Using an fstest.MapFS, config loading can be unit-tested without touching the disk.
+// The same code works with os.DirFS or an embed.FS.
+func ExamplePrimordius_FromFS() {
+	fsys := fstest.MapFS{
+		"config/app.yaml": {Data: []byte("host: example.com\nport: 8080")},
+	}
+
+	var cfg struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	}
+	pr := primordius.New(&cfg)
+	pr.FromFS(fsys, "config/app.yaml")
+	if err := pr.Process(); err != nil {
+		log.Fatal(err)
+	}
+
+	fmt.Println(cfg.Host, cfg.Port)
+	// Output: example.com 8080
+}
@@ -0,0 +1,91 @@
+package primordius
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ProcessFields works like Process but only assigns the fields named by include to
+// the target, leaving all other fields untouched. Fields are named by their dotted
+// path, e.g. "LogLevel" or "Features.Beta"; naming a struct field includes all of
+// its nested fields. This allows e.g. reloading only the hot-reloadable part of a
+// configuration.
+//
+// All sources are processed into a copy of the target, so validation applies to
+// the configuration as a whole, including fields not assigned.
+func (pr *Primordius) ProcessFields(include ...string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if err := checkTarget(pr.target); err != nil {
+		return err
+	}
+	tv := reflect.ValueOf(pr.target)
+
+	tmp := reflect.New(tv.Elem().Type())
+	c, err := deepCopy(tv.Elem())
+	if err != nil {
+		return err
+	}
+	tmp.Elem().Set(c)
+	if err := pr.process(tmp.Interface()); err != nil {
+		return err
+	}
+
+	dst := make([]reflect.Value, 0, len(include))
+	src := make([]reflect.Value, 0, len(include))
+	for _, path := range include {
+		s, err := fieldByPath(tmp.Elem(), path, false)
+		if err != nil {
+			return err
+		}
+		src = append(src, s)
+	}
+	for _, path := range include {
+		d, err := fieldByPath(tv.Elem(), path, true)
+		if err != nil {
+			return err
+		}
+		dst = append(dst, d)
+	}
+	for i := range dst {
+		dst[i].Set(src[i])
+	}
+	pr.trace.restrict(include)
+	fingerprint, err := takeSnapshot(pr.target)
+	if err != nil {
+		return err
+	}
+	pr.fingerprint = fingerprint
+
+	return nil
+}
+
+// fieldByPath returns the field of the struct s at the dotted path, descending
+// through pointers to structs. If alloc is true, nil pointers on the way are
+// allocated; otherwise the zero value of the field type is returned for them.
+func fieldByPath(s reflect.Value, path string, alloc bool) (reflect.Value, error) {
+	v := s
+	for _, name := range strings.Split(path, ".") {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				if !alloc {
+					v = reflect.Zero(v.Type().Elem())
+					continue
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		if v.Kind() != reflect.Struct {
+			return reflect.Value{}, fmt.Errorf("unknown field %q", path)
+		}
+		sf, ok := v.Type().FieldByName(name)
+		if !ok || !sf.IsExported() || len(sf.Index) != 1 {
+			return reflect.Value{}, fmt.Errorf("unknown field %q", path)
+		}
+		v = v.Field(sf.Index[0])
+	}
+
+	return v, nil
+}
@@ -0,0 +1,44 @@
+package primordius
+
+import "testing"
+
+func TestPrimordius_ProcessFields(t *testing.T) {
+	type features struct {
+		Beta  bool `yaml:"beta"`
+		Alpha bool `yaml:"alpha"`
+	}
+	type target struct {
+		LogLevel string    `yaml:"log_level"`
+		DSN      string    `yaml:"dsn"`
+		Features *features `yaml:"features"`
+	}
+
+	tests := []struct {
+		name    string
+		include []string
+		want    target
+		wantErr bool
+	}{
+		{"single field", []string{"LogLevel"}, target{LogLevel: "debug", DSN: "old"}, false},
+		{"nested field", []string{"Features.Beta"}, target{DSN: "old", Features: &features{Beta: true}}, false},
+		{"whole struct", []string{"Features"}, target{DSN: "old", Features: &features{Beta: true, Alpha: true}}, false},
+		{"unknown field", []string{"Nope"}, target{DSN: "old"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := target{DSN: "old"}
+			pr := New(&got)
+			pr.FromYAML([]byte("log_level: debug\ndsn: new\nfeatures:\n  beta: true\n  alpha: true"))
+			if err := pr.ProcessFields(tc.include...); (err != nil) != tc.wantErr {
+				t.Fatalf("ProcessFields() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got.LogLevel != tc.want.LogLevel || got.DSN != tc.want.DSN {
+				t.Errorf("ProcessFields() got = %+v, want %+v", got, tc.want)
+			}
+			if (got.Features == nil) != (tc.want.Features == nil) || got.Features != nil && *got.Features != *tc.want.Features {
+				t.Errorf("ProcessFields() got.Features = %+v, want %+v", got.Features, tc.want.Features)
+			}
+		})
+	}
+}
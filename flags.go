@@ -0,0 +1,215 @@
+package primordius
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const flagTagName = "flag"
+
+type (
+	flagSource struct {
+		args []string
+		conv *converter
+	}
+	// flagField is a field set by a command-line flag.
+	flagField struct {
+		f    reflect.Value
+		path string
+		opts tagOptions
+	}
+	// flagValue records the value of a flag, implementing flag.Value.
+	flagValue struct {
+		name   string
+		isBool bool
+		values map[string]string
+	}
+)
+
+func (fv *flagValue) String() string { return "" }
+
+func (fv *flagValue) Set(val string) error {
+	fv.values[fv.name] = val
+	return nil
+}
+
+func (fv *flagValue) IsBoolFlag() bool { return fv.isBool }
+
+func (fs *flagSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+	fields := make(map[string][]flagField)
+	if err := fs.conv.collectFlags(v.Elem(), "", 0, make(map[visit]bool), fields); err != nil {
+		return err
+	}
+
+	set := flag.NewFlagSet("primordius", flag.ContinueOnError)
+	set.SetOutput(io.Discard)
+	values := make(map[string]string)
+	isBool := make(map[string]bool, len(fields))
+	for name, ff := range fields {
+		isBool[name] = true
+		for _, field := range ff {
+			isBool[name] = isBool[name] && field.f.Kind() == reflect.Bool
+		}
+		set.Var(&flagValue{name: name, isBool: isBool[name], values: values}, name, "")
+	}
+	args := fs.args
+	if fs.conv != nil && fs.conv.ignoreUnknownFlags {
+		args = knownFlags(args, isBool)
+	}
+	if err := set.Parse(args); err != nil {
+		return err
+	}
+
+	// apply the flags in a stable order so the same invalid flag is always reported first
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, field := range fields[name] {
+			if err := fs.conv.assign(field.f, field.path, name, values[name], field.opts); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectFlags adds the fields of the struct s which are set by flags to fields, keyed
+// by the flag name, descending into nested structs like the env source.
+func (c *converter) collectFlags(s reflect.Value, path string, depth int, visited map[visit]bool, fields map[string][]flagField) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		f := s.Field(i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		key, opts := parseTag(sf.Tag.Get(tagName))
+		name := sf.Tag.Get(flagTagName)
+		if name == "" && key == "" && sf.IsExported() {
+			key = c.mappedKey(f, fieldPath)
+		}
+		if name == "" && key != "-" && !strings.HasSuffix(key, "*") {
+			name = flagName(key)
+		}
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			if key == "" && sf.IsExported() {
+				if err := c.collectNestedFlags(f, fieldPath, depth, visited, fields); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if !f.CanSet() {
+			if c.errorOnUnsettable {
+				return unsettableError(sf)
+			}
+			continue
+		}
+		fields[name] = append(fields[name], flagField{f: f, path: fieldPath, opts: opts})
+	}
+
+	return nil
+}
+
+// collectNestedFlags descends into f if it is a struct or a non-nil pointer to one.
+func (c *converter) collectNestedFlags(f reflect.Value, path string, depth int, visited map[visit]bool, fields map[string][]flagField) error {
+	if f.Kind() == reflect.Pointer {
+		if f.IsNil() || !hasExportedFields(f.Type().Elem()) {
+			return nil
+		}
+		v := visit{typ: f.Type(), ptr: f.Pointer()}
+		if visited[v] {
+			return fmt.Errorf("%w: field %s", ErrPointerCycle, path)
+		}
+		visited[v] = true
+		defer delete(visited, v)
+		f = f.Elem()
+	}
+	if !hasExportedFields(f.Type()) {
+		return nil
+	}
+	if depth+1 > c.maxDepth() {
+		return fmt.Errorf("%w: field %s is nested more than %d levels deep", ErrMaxDepth, path, c.maxDepth())
+	}
+
+	return c.collectFlags(f, path, depth+1, visited, fields)
+}
+
+// flagName derives the name of a flag from the key of an env tag, e.g. "db-host" from "DB_HOST".
+func flagName(key string) string {
+	return strings.ToLower(strings.ReplaceAll(key, "_", "-"))
+}
+
+// knownFlags returns args without the flags not contained in isBool, which maps the
+// names of the known flags to whether they are bool flags. The argument following
+// an unknown flag without a value is considered its value and removed as well,
+// unless it looks like a flag itself.
+func knownFlags(args []string, isBool map[string]bool) []string {
+	known := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			// flag parsing stops here
+			return append(known, args[i:]...)
+		}
+		name, _, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		takesValue := !hasValue && i+1 < len(args)
+		if boolFlag, ok := isBool[name]; ok {
+			known = append(known, arg)
+			if takesValue && !boolFlag {
+				i++
+				known = append(known, args[i])
+			}
+			continue
+		}
+		if takesValue && !strings.HasPrefix(args[i+1], "-") {
+			i++
+		}
+	}
+
+	return known
+}
+
+// SetIgnoreUnknownFlags controls whether flag sources skip flags which don't match any
+// field. By default, unknown flags make processing fail.
+func (pr *Primordius) SetIgnoreUnknownFlags(ignore bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.ignoreUnknownFlags = ignore
+}
+
+// FromFlags adds a Source to pr which reads values from command-line flags in args,
+// e.g. os.Args[1:], using the flag package. Flags are named by the 'flag' tag or else
+// derived from the 'env' tag by converting it to lower case and replacing underscores
+// with hyphens, e.g. --db-host for DB_HOST. Both -name and --name as well as the forms
+// --name=value and --name value are supported; bool flags don't require a value.
+// Values are converted like those of environment variables. Parsing stops at the first
+// non-flag argument.
+func (pr *Primordius) FromFlags(args []string) {
+	pr.AddSource(&flagSource{args: args, conv: pr.conv})
+}
+
+// FromEnvAndFlags adds the Sources of FromEnv with prefix and FromFlags with args to
+// pr, in this order, so flags override env vars, which in turn override all sources
+// added before, such as files.
+func (pr *Primordius) FromEnvAndFlags(prefix string, args []string) {
+	pr.FromEnv(prefix)
+	pr.FromFlags(args)
+}
@@ -0,0 +1,125 @@
+package primordius
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrimordius_FromFlags(t *testing.T) {
+	type database struct {
+		Host string `env:"DB_HOST"`
+	}
+	type target struct {
+		Port     int           `env:"PORT"`
+		Debug    bool          `env:"DEBUG"`
+		Timeout  time.Duration `flag:"timeout"`
+		Level    string        `env:"LOG_LEVEL,upper"`
+		Secret   string        `env:"SECRET" flag:"-"`
+		Database database
+	}
+
+	tests := []struct {
+		name          string
+		args          []string
+		ignoreUnknown bool
+		want          target
+		wantErr       bool
+	}{
+		{
+			name: "all forms",
+			args: []string{"-port=80", "--debug", "--timeout", "5s", "--log-level", "warn", "--db-host=db.local"},
+			want: target{Port: 80, Debug: true, Timeout: 5 * time.Second, Level: "WARN", Database: database{Host: "db.local"}},
+		},
+		{
+			name: "explicit bool value and positional arguments",
+			args: []string{"--debug=false", "--port", "81", "serve", "--port=82"},
+			want: target{Port: 81},
+		},
+		{name: "unknown flag", args: []string{"--port=80", "--verbose"}, wantErr: true},
+		{name: "excluded flag", args: []string{"--secret=x"}, wantErr: true},
+		{name: "invalid value", args: []string{"--port=eighty"}, wantErr: true},
+		{
+			name:          "ignored unknown flags",
+			args:          []string{"--verbose", "--port", "80", "--color", "auto", "--format=json", "--debug"},
+			ignoreUnknown: true,
+			want:          target{Port: 80, Debug: true},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.SetIgnoreUnknownFlags(tc.ignoreUnknown)
+			pr.FromFlags(tc.args)
+			err := pr.Process()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromFlags_errorOrder(t *testing.T) {
+	var target struct {
+		Port    int           `env:"PORT"`
+		Timeout time.Duration `flag:"timeout"`
+		Workers int           `env:"WORKERS"`
+	}
+	// the first invalid flag by name is reported, regardless of map iteration order
+	for i := 0; i < 20; i++ {
+		pr := New(&target)
+		pr.FromFlags([]string{"--workers=many", "--timeout=long", "--port=eighty"})
+		if err := pr.Process(); err == nil || !strings.Contains(err.Error(), "field Port") {
+			t.Fatalf("Process() error = %v, want error for field Port", err)
+		}
+	}
+}
+
+func Test_knownFlags(t *testing.T) {
+	isBool := map[string]bool{"port": false, "debug": true}
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"only known", []string{"--port", "80", "--debug"}, []string{"--port", "80", "--debug"}},
+		{"unknown with value", []string{"--color", "auto", "-port=80"}, []string{"-port=80"}},
+		{"unknown followed by flag", []string{"--verbose", "--debug"}, []string{"--debug"}},
+		{"terminator", []string{"--x=1", "--", "--y"}, []string{"--", "--y"}},
+		{"positional", []string{"--debug", "serve", "--x"}, []string{"--debug", "serve", "--x"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := knownFlags(tc.args, isBool); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("knownFlags() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromEnvAndFlags(t *testing.T) {
+	t.Setenv("EAF_PORT", "81")
+	t.Setenv("EAF_HOST", "env.local")
+	var got struct {
+		Port  int    `json:"port" env:"PORT"`
+		Host  string `json:"host" env:"HOST"`
+		Debug bool   `json:"debug" env:"DEBUG"`
+	}
+
+	pr := New(&got)
+	pr.FromJSON([]byte(`{"port": 80, "host": "file.local", "debug": true}`))
+	pr.FromEnvAndFlags("EAF", []string{"--port=82"})
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if got.Port != 82 || got.Host != "env.local" || !got.Debug {
+		t.Errorf("Process() got = %+v", got)
+	}
+}
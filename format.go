@@ -0,0 +1,204 @@
+package primordius
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Format names an encoding used to decode raw configuration content.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatTOML Format = "toml"
+)
+
+var (
+	ErrUnknownFormat = errors.New("unknown format")
+	ErrDecode        = errors.New("decoding failed")
+)
+
+// loadingSource is implemented by sources which obtain raw content that is
+// decoded into the target according to a Format.
+type loadingSource interface {
+	Source
+	// load returns the raw content and the Format it is encoded in.
+	// nil content means there is nothing to decode.
+	load() ([]byte, Format, error)
+}
+
+// decodeSource loads the raw content of ls and decodes it into t.
+func decodeSource(ls loadingSource, t any) error {
+	cont, format, err := ls.load()
+	if err != nil || cont == nil {
+		return err
+	}
+	if err := decode(format, cont, t); err != nil {
+		return &decodeError{err: err}
+	}
+
+	return nil
+}
+
+// decodeError marks an error of decoding content as matching ErrDecode without
+// changing its message.
+type decodeError struct {
+	err error
+}
+
+func (de *decodeError) Error() string {
+	return de.err.Error()
+}
+
+func (de *decodeError) Unwrap() error {
+	return de.err
+}
+
+func (de *decodeError) Is(target error) bool {
+	return target == ErrDecode
+}
+
+// DecodeFunc decodes content into t, which is a pointer.
+type DecodeFunc func(content []byte, t any) error
+
+var (
+	codecsMu sync.RWMutex
+	codecs   = make(map[Format]DecodeFunc)
+)
+
+func init() {
+	RegisterCodec(FormatJSON, json.Unmarshal)
+	RegisterCodec(FormatYAML, yaml.Unmarshal)
+	RegisterCodec(FormatTOML, func(content []byte, t any) error {
+		_, err := toml.Decode(string(content), t)
+		return err
+	})
+}
+
+// RegisterCodec registers fn as the decoder for format, replacing any decoder
+// registered before, so that sources can decode content in a format the package
+// doesn't support by itself, e.g.
+//
+//	primordius.RegisterCodec("msgpack", msgpack.Unmarshal)
+//	pr.FromFile("config.msgpack", "msgpack")
+//
+// Files whose extension equals the format name are recognized as well.
+// RegisterCodec is safe for concurrent use.
+func RegisterCodec(format Format, fn DecodeFunc) {
+	codecsMu.Lock()
+	defer codecsMu.Unlock()
+	codecs[format] = fn
+}
+
+// codec returns the decoder registered for format.
+func codec(format Format) (DecodeFunc, bool) {
+	codecsMu.RLock()
+	defer codecsMu.RUnlock()
+	fn, ok := codecs[format]
+	return fn, ok
+}
+
+// formatFromExt returns the Format matching the extension of the file name.
+// Extensions are matched case-insensitively.
+func formatFromExt(name string) (Format, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(name)), ".")
+	if ext == "yml" {
+		return FormatYAML, nil
+	}
+	if _, ok := codec(Format(ext)); ok && ext != "" {
+		return Format(ext), nil
+	}
+
+	return "", fmt.Errorf("%w: cannot infer format of %q from its extension", ErrUnknownFormat, name)
+}
+
+// utf8BOM is the byte order mark some editors, mostly on Windows, put at the start of UTF-8 files.
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// stripBOM removes a leading UTF-8 byte order mark from content.
+func stripBOM(content []byte) []byte {
+	return bytes.TrimPrefix(content, utf8BOM)
+}
+
+// decode unmarshals content into t using the decoder registered for format.
+// A leading UTF-8 byte order mark is ignored.
+func decode(format Format, content []byte, t any) error {
+	fn, ok := codec(format)
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	content = stripBOM(content)
+	if hasPolymorphicFields(reflect.TypeOf(t)) {
+		return decodePolymorphic(format, fn, content, t)
+	}
+
+	return fn(content, t)
+}
+
+// decodeMap decodes content into a generic map using fn and normalizes it. Numbers in
+// JSON content are kept as json.Number, so the map can be encoded again without
+// rounding them to float64.
+func decodeMap(format Format, fn DecodeFunc, content []byte) (map[string]any, error) {
+	raw := make(map[string]any)
+	if format != FormatJSON {
+		if err := fn(content, &raw); err != nil {
+			return nil, err
+		}
+		return normalizeMap(raw), nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(content))
+	dec.UseNumber()
+	if err := dec.Decode(&raw); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid data after top-level value")
+	}
+	return raw, nil
+}
+
+// normalizeMap converts nested maps with non-string keys, as produced by the YAML
+// decoder, into map[string]any recursively.
+func normalizeMap(m map[string]any) map[string]any {
+	for k, v := range m {
+		m[k] = normalizeValue(v)
+	}
+	return m
+}
+
+func normalizeValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		return normalizeMap(val)
+	case map[any]any:
+		m := make(map[string]any, len(val))
+		for k, v := range val {
+			m[fmt.Sprint(k)] = normalizeValue(v)
+		}
+		return m
+	case []any:
+		for i := range val {
+			val[i] = normalizeValue(val[i])
+		}
+		return val
+	case []map[string]any:
+		// arrays of tables as produced by the TOML decoder
+		s := make([]any, len(val))
+		for i := range val {
+			s[i] = normalizeMap(val[i])
+		}
+		return s
+	}
+	return v
+}
@@ -0,0 +1,47 @@
+package primordius
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_decode_byteOrderMark(t *testing.T) {
+	type target struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+	}
+	bom := "\xEF\xBB\xBF"
+	contents := map[Format]string{
+		FormatJSON: bom + `{"host": "example.com"}`,
+		FormatYAML: bom + "host: example.com",
+		FormatTOML: bom + `host = "example.com"`,
+	}
+	dir := t.TempDir()
+
+	for format, content := range contents {
+		name := filepath.Join(dir, "config."+string(format))
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write test file: %s", err.Error())
+		}
+		sources := map[string]Source{
+			"file":    &fileSource{name: name},
+			"content": &contentSource{content: []byte(content), format: format},
+			"reader":  &readerSource{r: bytes.NewBufferString(content), format: format},
+		}
+		for sourceName, s := range sources {
+			t.Run(string(format)+"/"+sourceName, func(t *testing.T) {
+				var got target
+				pr := New(&got)
+				pr.SetStrictTOML(true)
+				pr.AddSource(s)
+				if err := pr.Process(); err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+				if got.Host != "example.com" {
+					t.Errorf("Host = %q, want %q", got.Host, "example.com")
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package primordius
+
+import "io/fs"
+
+type fsSource struct {
+	fsys fs.FS
+	name string
+	// format is the Format of the file; empty means inferring it from the extension.
+	format Format
+}
+
+func (fss *fsSource) ToTarget(t any) error {
+	return decodeSource(fss, t)
+}
+
+func (fss *fsSource) describe() string {
+	return fss.name
+}
+
+func (fss *fsSource) load() ([]byte, Format, error) {
+	format := fss.format
+	if format == "" {
+		var err error
+		if format, err = formatFromExt(fss.name); err != nil {
+			return nil, "", err
+		}
+	}
+	cont, err := fs.ReadFile(fss.fsys, fss.name)
+	return cont, format, err
+}
+
+// FromFS adds a Source to pr which reads values from the file name in fsys. The
+// format is inferred from the file extension, e.g. .json, .yaml, .yml or .toml.
+// Any fs.FS can be used, e.g. os.DirFS, an embed.FS or, for tests, an fstest.MapFS.
+func (pr *Primordius) FromFS(fsys fs.FS, name string) {
+	pr.AddSource(&fsSource{fsys: fsys, name: name})
+}
+
+// FromYAMLFS adds a Source to pr which reads values from the YAML file name in fsys,
+// regardless of its extension.
+func (pr *Primordius) FromYAMLFS(fsys fs.FS, name string) {
+	pr.AddSource(&fsSource{fsys: fsys, name: name, format: FormatYAML})
+}
+
+// FromJSONFS adds a Source to pr which reads values from the JSON file name in fsys,
+// regardless of its extension.
+func (pr *Primordius) FromJSONFS(fsys fs.FS, name string) {
+	pr.AddSource(&fsSource{fsys: fsys, name: name, format: FormatJSON})
+}
+
+// FromTOMLFS adds a Source to pr which reads values from the TOML file name in fsys,
+// regardless of its extension.
+func (pr *Primordius) FromTOMLFS(fsys fs.FS, name string) {
+	pr.AddSource(&fsSource{fsys: fsys, name: name, format: FormatTOML})
+}
@@ -0,0 +1,62 @@
+package primordius
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+)
+
+func Test_fsSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+		Port int    `json:"port" yaml:"port" toml:"port"`
+	}
+
+	files := map[string]string{
+		"app.json": `{"host": "example.com", "port": 8080}`,
+		"app.YML":  "host: example.com\nport: 8080",
+		"app.toml": "host = \"example.com\"\nport = 8080",
+		"app.conf": "host = \"example.com\"",
+	}
+	dir := t.TempDir()
+	mapFS := fstest.MapFS{}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write test file: %s", err.Error())
+		}
+		mapFS[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	filesystems := map[string]fs.FS{"os.DirFS": os.DirFS(dir), "fstest.MapFS": mapFS}
+
+	tests := []struct {
+		name    string
+		file    string
+		format  Format
+		want    target
+		wantErr bool
+	}{
+		{"JSON file", "app.json", "", target{"example.com", 8080}, false},
+		{"YAML file with upper case extension", "app.YML", "", target{"example.com", 8080}, false},
+		{"TOML file", "app.toml", "", target{"example.com", 8080}, false},
+		{"unknown extension", "app.conf", "", target{}, true},
+		{"explicit format", "app.conf", FormatTOML, target{Host: "example.com"}, false},
+		{"explicit format overriding extension", "app.toml", FormatJSON, target{}, true},
+		{"missing file", "missing.json", "", target{}, true},
+	}
+
+	for fsName, fsys := range filesystems {
+		for _, tc := range tests {
+			t.Run(fsName+"/"+tc.name, func(t *testing.T) {
+				var got target
+				if err := (&fsSource{fsys: fsys, name: tc.file, format: tc.format}).ToTarget(&got); (err != nil) != tc.wantErr {
+					t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+				}
+				if got != tc.want {
+					t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package primordius
+
+import (
+	"io"
+	"os"
+)
+
+type (
+	fileSource struct {
+		name   string
+		format Format
+	}
+	contentSource struct {
+		content []byte
+		format  Format
+	}
+	readerSource struct {
+		r      io.Reader
+		format Format
+	}
+)
+
+func (fls *fileSource) ToTarget(t any) error {
+	return decodeSource(fls, t)
+}
+
+func (fls *fileSource) describe() string {
+	return fls.name
+}
+
+func (fls *fileSource) load() ([]byte, Format, error) {
+	format := fls.format
+	if format == "" {
+		var err error
+		if format, err = formatFromExt(fls.name); err != nil {
+			return nil, "", err
+		}
+	}
+	cont, err := os.ReadFile(fls.name)
+	return cont, format, err
+}
+
+func (cs *contentSource) ToTarget(t any) error {
+	return decodeSource(cs, t)
+}
+
+func (cs *contentSource) load() ([]byte, Format, error) {
+	return cs.content, cs.format, nil
+}
+
+func (rs *readerSource) ToTarget(t any) error {
+	return decodeSource(rs, t)
+}
+
+func (rs *readerSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(rs.r)
+	return cont, rs.format, err
+}
+
+// FromFile adds a Source to pr which reads values from a file in the given format,
+// which may be any format registered using RegisterCodec. If format is empty, it is
+// inferred from the file extension like FromFileAuto does.
+func (pr *Primordius) FromFile(name string, format Format) {
+	pr.AddSource(&fileSource{name: name, format: format})
+}
+
+// FromFileAuto adds a Source to pr which reads values from a file in the format
+// inferred from its extension, matched case-insensitively, e.g. .yaml or .yml, .json
+// and .toml. Process fails with an error wrapping ErrUnknownFormat if the extension is
+// unknown.
+func (pr *Primordius) FromFileAuto(name string) {
+	pr.AddSource(&fileSource{name: name})
+}
+
+// FromContent adds a Source to pr which reads values from a block of content in the
+// given format, which may be any format registered using RegisterCodec.
+func (pr *Primordius) FromContent(content []byte, format Format) {
+	pr.AddSource(&contentSource{content: content, format: format})
+}
+
+// FromReader adds a Source to pr which reads content in the given format from r. The
+// format may be any format registered using RegisterCodec.
+func (pr *Primordius) FromReader(r io.Reader, format Format) {
+	pr.AddSource(&readerSource{r: r, format: format})
+}
@@ -0,0 +1,129 @@
+package primordius
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRegisterCodec(t *testing.T) {
+	// a trivial "kv" format consisting of key:value lines
+	RegisterCodec("kv", func(content []byte, t any) error {
+		m, ok := t.(*map[string]string)
+		if !ok {
+			return errors.New("kv: unsupported target")
+		}
+		*m = make(map[string]string)
+		for _, line := range strings.Split(string(content), "\n") {
+			if k, v, ok := strings.Cut(line, ":"); ok {
+				(*m)[k] = v
+			}
+		}
+		return nil
+	})
+	t.Cleanup(func() {
+		codecsMu.Lock()
+		defer codecsMu.Unlock()
+		delete(codecs, "kv")
+	})
+
+	name := filepath.Join(t.TempDir(), "config.KV")
+	if err := os.WriteFile(name, []byte("host:example.com"), 0600); err != nil {
+		t.Fatalf("failed to write test file: %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{"file with explicit format", &fileSource{name: name, format: "kv"}, false},
+		{"file with inferred format", &fileSource{name: name}, false},
+		{"content", &contentSource{content: []byte("host:example.com"), format: "kv"}, false},
+		{"reader", &readerSource{r: strings.NewReader("host:example.com"), format: "kv"}, false},
+		{"unregistered format", &contentSource{content: []byte("host:example.com"), format: "hjson"}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got map[string]string
+			err := tc.source.ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if tc.wantErr {
+				if !errors.Is(err, ErrUnknownFormat) {
+					t.Errorf("ToTarget() error = %v, want %v", err, ErrUnknownFormat)
+				}
+				return
+			}
+			if got["host"] != "example.com" {
+				t.Errorf("ToTarget() got = %v, want host:example.com", got)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"app.Yaml": "port: 80",
+		"app.yml":  "port: 81",
+		"app.JSON": `{"port": 82}`,
+		"app.toml": "port = 83",
+		"app.conf": "port = 84",
+		"app":      "port = 85",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write test file: %s", err.Error())
+		}
+	}
+
+	auto := (*Primordius).FromFileAuto
+	withFormat := func(format Format) func(pr *Primordius, name string) {
+		return func(pr *Primordius, name string) { pr.FromFile(name, format) }
+	}
+
+	tests := []struct {
+		name    string
+		file    string
+		add     func(pr *Primordius, name string)
+		want    int
+		wantErr error
+	}{
+		{"yaml", "app.Yaml", auto, 80, nil},
+		{"yml", "app.yml", auto, 81, nil},
+		{"json", "app.JSON", auto, 82, nil},
+		{"toml", "app.toml", auto, 83, nil},
+		{"unknown extension", "app.conf", auto, 0, ErrUnknownFormat},
+		{"no extension", "app", auto, 0, ErrUnknownFormat},
+		{"empty format", "app.toml", withFormat(""), 83, nil},
+		{"explicit format", "app.conf", withFormat(FormatTOML), 84, nil},
+		{"missing file", "missing.yaml", auto, 0, errAny},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got struct {
+				Port int `yaml:"port" json:"port" toml:"port"`
+			}
+			pr := New(&got)
+			tc.add(pr, filepath.Join(dir, tc.file))
+			err := pr.Process()
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("Process() error = %v, want nil", err)
+			case tc.wantErr == errAny && err == nil:
+				t.Fatalf("Process() error = nil, want an error")
+			case tc.wantErr != nil && tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+				t.Fatalf("Process() error = %v, want %v", err, tc.wantErr)
+			}
+			if got.Port != tc.want {
+				t.Errorf("Port = %d, want %d", got.Port, tc.want)
+			}
+		})
+	}
+}
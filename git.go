@@ -0,0 +1,95 @@
+package primordius
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+type (
+	// GitClient is the subset of git operations required by the git source.
+	GitClient interface {
+		// ReadFile returns the content of the file at path in the repository at
+		// repoURL as of ref, e.g. a branch or tag.
+		ReadFile(ctx context.Context, repoURL, ref, path string) ([]byte, error)
+	}
+	// GitCLI is a GitClient using the git executable found in the PATH. Each call
+	// fetches ref into a temporary repository with a depth of 1, so no working copy
+	// is kept around. Credentials are taken from the git configuration as usual.
+	// Fetching a commit hash requires the server to allow it, which most hosting
+	// services do.
+	GitCLI    struct{}
+	gitSource struct {
+		client  GitClient
+		repoURL string
+		ref     string
+		path    string
+		format  Format
+	}
+)
+
+func (GitCLI) ReadFile(ctx context.Context, repoURL, ref, path string) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "primordius-git-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	git := func(args ...string) ([]byte, error) {
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("git %s: %w: %s", args[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return out, nil
+	}
+	if _, err := git("init", "-q"); err != nil {
+		return nil, err
+	}
+	if _, err := git("fetch", "-q", "--depth", "1", "--", repoURL, ref); err != nil {
+		return nil, err
+	}
+
+	return git("show", "FETCH_HEAD:"+path)
+}
+
+func (gs *gitSource) ToTarget(t any) error {
+	return decodeSource(gs, t)
+}
+
+func (gs *gitSource) describe() string {
+	return redactURL(gs.repoURL) + "@" + gs.ref + ":" + gs.path
+}
+
+func (gs *gitSource) load() ([]byte, Format, error) {
+	format := gs.format
+	if format == "" {
+		var err error
+		if format, err = formatFromExt(gs.path); err != nil {
+			return nil, "", err
+		}
+	}
+	cont, err := gs.client.ReadFile(context.Background(), gs.repoURL, gs.ref, gs.path)
+	if err != nil {
+		return nil, format, fmt.Errorf("reading %s at %s from %s: %w", gs.path, gs.ref, gs.repoURL, err)
+	}
+
+	return cont, format, nil
+}
+
+// FromGit adds a Source to pr which reads values from the file at path in the git
+// repository at repoURL as of ref, e.g. a tag pinning the configuration, using GitCLI.
+// If format is empty, it is inferred from the file extension.
+func (pr *Primordius) FromGit(repoURL, ref, path string, format Format) {
+	pr.FromGitWithClient(GitCLI{}, repoURL, ref, path, format)
+}
+
+// FromGitWithClient works like FromGit but performs the git operations using client.
+func (pr *Primordius) FromGitWithClient(client GitClient, repoURL, ref, path string, format Format) {
+	pr.AddSource(&gitSource{client: client, repoURL: repoURL, ref: ref, path: path, format: format})
+}
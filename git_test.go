@@ -0,0 +1,104 @@
+package primordius
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+type fakeGit map[string]string
+
+func (fg fakeGit) ReadFile(_ context.Context, repoURL, ref, path string) ([]byte, error) {
+	v, ok := fg[repoURL+"@"+ref+":"+path]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return []byte(v), nil
+}
+
+func Test_gitSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `json:"host" yaml:"host"`
+		Port int    `json:"port" yaml:"port"`
+	}
+
+	client := fakeGit{
+		"repo@v1:app.yaml":   "host: localhost\nport: 80",
+		"repo@v2:app.conf":   `{"host": "example.com", "port": 8080}`,
+		"repo@v2:broken.yml": "port: [",
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		want    target
+		wantErr bool
+	}{
+		{"inferred format", &gitSource{client: client, repoURL: "repo", ref: "v1", path: "app.yaml"}, target{"localhost", 80}, false},
+		{"explicit format", &gitSource{client: client, repoURL: "repo", ref: "v2", path: "app.conf", format: FormatJSON}, target{"example.com", 8080}, false},
+		{"unknown ref", &gitSource{client: client, repoURL: "repo", ref: "v3", path: "app.yaml"}, target{}, true},
+		{"invalid content", &gitSource{client: client, repoURL: "repo", ref: "v2", path: "broken.yml"}, target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := tc.source.ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestGitCLI_ReadFile(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git executable not available")
+	}
+
+	repo := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", append([]string{"-C", repo}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %s: %s", args, err, out)
+		}
+	}
+	write := func(content string) {
+		if err := os.WriteFile(filepath.Join(repo, "app.yaml"), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	run("init", "-q")
+	write("port: 80")
+	run("add", "app.yaml")
+	run("commit", "-q", "-m", "initial")
+	run("tag", "v1")
+	write("port: 8080")
+	run("commit", "-q", "-am", "change port")
+
+	got, err := GitCLI{}.ReadFile(context.Background(), repo, "v1", "app.yaml")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if string(got) != "port: 80" {
+		t.Errorf("ReadFile() = %q, want %q", got, "port: 80")
+	}
+	if _, err := (GitCLI{}).ReadFile(context.Background(), repo, "v1", "missing.yaml"); err == nil {
+		t.Error("ReadFile() error = nil, want error for missing file")
+	}
+
+	marker := filepath.Join(t.TempDir(), "marker")
+	if _, err := (GitCLI{}).ReadFile(context.Background(), "--upload-pack=touch "+marker, "v1", "app.yaml"); err == nil {
+		t.Error("ReadFile() error = nil, want error for option as repository")
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("ReadFile() passed the repository as option to git")
+	}
+}
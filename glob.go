@@ -0,0 +1,95 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+type globSource struct {
+	pattern string
+	// hints maps patterns matching base names of files to their formats.
+	hints map[string]Format
+}
+
+func (gs *globSource) ToTarget(t any) error {
+	names, err := filepath.Glob(gs.pattern)
+	if err != nil {
+		return err
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		format, err := gs.format(name)
+		if errors.Is(err, ErrUnknownFormat) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		fi, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		if fi.IsDir() {
+			continue
+		}
+		cont, err := os.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		if err := decode(format, cont, t); err != nil {
+			return fmt.Errorf("file %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+func (gs *globSource) describe() string {
+	return gs.pattern
+}
+
+// format returns the format of the file name, preferring the hints of gs over the
+// extension. A hint naming the base name exactly takes precedence over patterns.
+func (gs *globSource) format(name string) (Format, error) {
+	base := filepath.Base(name)
+	if format, ok := gs.hints[base]; ok {
+		return format, nil
+	}
+	patterns := make([]string, 0, len(gs.hints))
+	for pattern := range gs.hints {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+	for _, pattern := range patterns {
+		matched, err := filepath.Match(pattern, base)
+		if err != nil {
+			return "", fmt.Errorf("format hint %q: %w", pattern, err)
+		}
+		if matched {
+			return gs.hints[pattern], nil
+		}
+	}
+
+	return formatFromExt(name)
+}
+
+// FromGlob adds a Source to pr which reads values from all files matching pattern,
+// e.g. "conf.d/*.yaml", in lexical order, so later files override earlier ones. The
+// format of each file is inferred from its extension; files with an unknown extension
+// and directories are skipped. The pattern syntax is that of filepath.Match. If no file
+// matches, the source does nothing.
+func (pr *Primordius) FromGlob(pattern string) {
+	pr.AddSource(&globSource{pattern: pattern})
+}
+
+// FromGlobWithFormats works like FromGlob, but the formats of files are looked up in
+// hints first, which maps base names of files or patterns matching them to formats,
+// e.g. {"legacy.conf": primordius.FormatTOML} or {"*.cfg": primordius.FormatYAML}.
+// Files without a matching hint fall back to inferring the format from their extension.
+func (pr *Primordius) FromGlobWithFormats(pattern string, hints map[string]Format) {
+	pr.AddSource(&globSource{pattern: pattern, hints: hints})
+}
@@ -0,0 +1,109 @@
+package primordius
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_globSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host  string `json:"host" yaml:"host"`
+		Port  int    `json:"port" yaml:"port"`
+		Debug bool   `json:"debug" yaml:"debug"`
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"10-base.yaml":     "host: example.com\nport: 80",
+		"20-port.json":     `{"port": 8080}`,
+		"30-debug.yaml":    "debug: true",
+		"README.md":        "# not a config file",
+		"broken/50.yaml":   "port: [",
+		"nested.yaml/x.md": "",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write test file: %s", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		pattern string
+		want    target
+		wantErr bool
+	}{
+		{"all files", filepath.Join(dir, "*"), target{"example.com", 8080, true}, false},
+		{"YAML files only", filepath.Join(dir, "*.yaml"), target{"example.com", 80, true}, false},
+		{"no match", filepath.Join(dir, "*.toml"), target{}, false},
+		{"decode failure", filepath.Join(dir, "broken", "*.yaml"), target{}, true},
+		{"malformed pattern", filepath.Join(dir, "["), target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&globSource{pattern: tc.pattern}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_globSource_format(t *testing.T) {
+	hints := map[string]Format{
+		"legacy.conf": FormatTOML,
+		"*.conf":      FormatYAML,
+		"*.cfg":       FormatJSON,
+	}
+
+	tests := []struct {
+		name    string
+		hints   map[string]Format
+		file    string
+		want    Format
+		wantErr bool
+	}{
+		{"exact name", hints, "conf.d/legacy.conf", FormatTOML, false},
+		{"pattern", hints, "conf.d/other.conf", FormatYAML, false},
+		{"hint overrides extension", map[string]Format{"*.json": FormatYAML}, "app.json", FormatYAML, false},
+		{"extension fallback", hints, "conf.d/app.yml", FormatYAML, false},
+		{"unknown", hints, "conf.d/app.hjson", "", true},
+		{"malformed hint", map[string]Format{"[": FormatJSON}, "app.json", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := (&globSource{hints: tc.hints}).format(tc.file)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("format() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("format() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.conf"), []byte("port = 8080"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	var target struct {
+		Port int `toml:"port"`
+	}
+	s := &globSource{pattern: filepath.Join(dir, "*"), hints: map[string]Format{"app.conf": FormatTOML}}
+	if err := s.ToTarget(&target); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+	if target.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", target.Port)
+	}
+}
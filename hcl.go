@@ -0,0 +1,189 @@
+package primordius
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"io"
+	"os"
+	"reflect"
+	"time"
+)
+
+const FormatHCL Format = "hcl"
+
+type (
+	hclFileSource struct {
+		name string
+	}
+	hclContentSource struct {
+		content []byte
+	}
+	hclReaderSource struct {
+		r io.Reader
+	}
+)
+
+func init() {
+	RegisterCodec(FormatHCL, decodeHCL)
+}
+
+func (hs *hclFileSource) ToTarget(t any) error {
+	return decodeSource(hs, t)
+}
+
+func (hs *hclFileSource) describe() string {
+	return hs.name
+}
+
+func (hs *hclFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(hs.name)
+	return cont, FormatHCL, err
+}
+
+func (hs *hclFileSource) modTime() time.Time { return fileModTime(hs.name) }
+
+func (hs *hclContentSource) ToTarget(t any) error {
+	return decodeSource(hs, t)
+}
+
+func (hs *hclContentSource) load() ([]byte, Format, error) {
+	return hs.content, FormatHCL, nil
+}
+
+func (hs *hclReaderSource) ToTarget(t any) error {
+	return decodeSource(hs, t)
+}
+
+func (hs *hclReaderSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(hs.r)
+	return cont, FormatHCL, err
+}
+
+// decodeHCL decodes HCL content in native syntax into t, which points to a struct,
+// using the hcl tags of its fields as defined by gohcl, or to a map[string]any, which
+// receives the plain values of all attributes and blocks as described at hclValues.
+// Variables and functions are not supported in expressions.
+func decodeHCL(content []byte, t any) error {
+	v := reflect.ValueOf(t)
+	_, isMap := t.(*map[string]any)
+	if v.Kind() != reflect.Pointer || v.IsNil() || (v.Elem().Kind() != reflect.Struct && !isMap) {
+		return ErrInvalidSpecification
+	}
+
+	file, diags := hclsyntax.ParseConfig(content, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return diagnosticsError(diags)
+	}
+	if m, ok := t.(*map[string]any); ok {
+		values, err := hclValues(file.Body.(*hclsyntax.Body))
+		if err != nil {
+			return err
+		}
+		if *m == nil {
+			*m = make(map[string]any, len(values))
+		}
+		for k, v := range values {
+			(*m)[k] = v
+		}
+		return nil
+	}
+	if diags := gohcl.DecodeBody(file.Body, nil, t); diags.HasErrors() {
+		return diagnosticsError(diags)
+	}
+
+	return nil
+}
+
+// hclValues returns the values of the attributes of body, converted like JSON values,
+// and its blocks as nested maps keyed by block type and then by each label, e.g.
+// service "api" { port = 80 } as {"service": {"api": {"port": 80}}}. Several blocks
+// at the same key are collected in a list.
+func hclValues(body *hclsyntax.Body) (map[string]any, error) {
+	m := make(map[string]any, len(body.Attributes)+len(body.Blocks))
+	for name, attr := range body.Attributes {
+		val, diags := attr.Expr.Value(nil)
+		if diags.HasErrors() {
+			return nil, diagnosticsError(diags)
+		}
+		raw, err := json.Marshal(ctyjson.SimpleJSONValue{Value: val})
+		if err != nil {
+			return nil, fmt.Errorf("hcl: attribute %s: %w", name, err)
+		}
+		var v any
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, fmt.Errorf("hcl: attribute %s: %w", name, err)
+		}
+		m[name] = v
+	}
+
+	for _, block := range body.Blocks {
+		sub, err := hclValues(block.Body)
+		if err != nil {
+			return nil, err
+		}
+		dst, key := m, block.Type
+		for _, label := range block.Labels {
+			next, ok := dst[key].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				dst[key] = next
+			}
+			dst, key = next, label
+		}
+		switch existing := dst[key].(type) {
+		case nil:
+			dst[key] = sub
+		case []any:
+			dst[key] = append(existing, sub)
+		default:
+			dst[key] = []any{existing, sub}
+		}
+	}
+
+	return m, nil
+}
+
+// diagnosticsError joins the error diagnostics of diags into a single error.
+func diagnosticsError(diags hcl.Diagnostics) error {
+	errs := make([]error, 0, len(diags))
+	for _, diag := range diags {
+		if diag.Severity != hcl.DiagError {
+			continue
+		}
+		msg := diag.Summary
+		if diag.Detail != "" {
+			msg += ": " + diag.Detail
+		}
+		if diag.Subject != nil {
+			msg = fmt.Sprintf("line %d, column %d: %s", diag.Subject.Start.Line, diag.Subject.Start.Column, msg)
+		}
+		errs = append(errs, errors.New("hcl: "+msg))
+	}
+
+	return errors.Join(errs...)
+}
+
+// FromHCLFile adds a Source to pr which reads values from an HCL file, e.g. one shared
+// with Terraform configurations. Fields are matched by their hcl tag, e.g.
+// `hcl:"port,optional"` for attributes or `hcl:"database,block"` for blocks, as
+// defined by gohcl. Attributes not marked as optional are required, so mark all
+// attributes optional which other sources may provide. Attributes and blocks without
+// a matching field are an error. All problems found are reported in a single error.
+func (pr *Primordius) FromHCLFile(name string) {
+	pr.AddSource(&hclFileSource{name: name})
+}
+
+// FromHCL adds a Source to pr which reads values from an HCL block.
+func (pr *Primordius) FromHCL(content []byte) {
+	pr.AddSource(&hclContentSource{content: content})
+}
+
+// FromHCLReader adds a Source to pr which reads HCL content from r.
+func (pr *Primordius) FromHCLReader(r io.Reader) {
+	pr.AddSource(&hclReaderSource{r: r})
+}
@@ -0,0 +1,178 @@
+package primordius
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_hclSources(t *testing.T) {
+	type database struct {
+		Host string `hcl:"host"`
+		Port int    `hcl:"port,optional"`
+	}
+	type target struct {
+		Name     string            `hcl:"name,optional"`
+		Tags     []string          `hcl:"tags,optional"`
+		Labels   map[string]string `hcl:"labels,optional"`
+		Database *database         `hcl:"database,block"`
+	}
+	content := `name = "app"
+tags = ["a", "b"]
+labels = {
+  team = "core"
+}
+
+database {
+  host = "db.local"
+  port = 5432
+}
+`
+	want := target{Name: "app", Tags: []string{"a", "b"}, Labels: map[string]string{"team": "core"},
+		Database: &database{Host: "db.local", Port: 5432}}
+	name := filepath.Join(t.TempDir(), "config.hcl")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{"file", &hclFileSource{name: name}, false},
+		{"content", &hclContentSource{content: []byte(content)}, false},
+		{"reader", &hclReaderSource{r: strings.NewReader(content)}, false},
+		{"generic file", &fileSource{name: name}, false},
+		{"missing file", &hclFileSource{name: name + ".missing"}, true},
+		{"syntax error", &hclContentSource{content: []byte("name = ")}, true},
+		{"invalid value", &hclContentSource{content: []byte(`database { port = "x" }`)}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := tc.source.ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func Test_decodeHCL_diagnostics(t *testing.T) {
+	var target struct {
+		Port int `hcl:"port"`
+	}
+	err := decodeHCL([]byte("port = \"x\"\nhost = \"db\"\n"), &target)
+	if err == nil {
+		t.Fatal("decodeHCL() error = nil, want error")
+	}
+	// all problems are reported at once
+	for _, want := range []string{"hcl: line 1, column 9: Unsuitable value type", "hcl: line 2, column 1: Unsupported argument"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("decodeHCL() error = %q, want it to contain %q", err, want)
+		}
+	}
+}
+
+func TestPrimordius_FromHCL_precedence(t *testing.T) {
+	var target struct {
+		Name string `hcl:"name,optional" env:"NAME"`
+		Port int    `hcl:"port,optional" env:"PORT"`
+	}
+	t.Setenv("HCL_NAME", "env")
+	t.Setenv("HCL_PORT", "1")
+	pr := New(&target)
+	pr.FromEnv("HCL")
+	pr.FromHCL([]byte(`port = 8080`))
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if target.Name != "env" || target.Port != 8080 {
+		t.Errorf("Process() got = %+v, want Name env and Port 8080", target)
+	}
+}
+
+func TestPrimordius_FromHCL_rawData(t *testing.T) {
+	var target struct {
+		Name     string `hcl:"name,optional"`
+		Database *struct {
+			Host string `hcl:"host"`
+		} `hcl:"database,block"`
+		Services []struct {
+			Name string `hcl:"name,label"`
+			Port int    `hcl:"port"`
+		} `hcl:"service,block"`
+	}
+	pr := New(&target)
+	pr.SetCaptureRawData(true)
+	pr.FromHCL([]byte(`name = "app"
+database {
+  host = "db.local"
+}
+service "api" {
+  port = 80
+}
+service "web" {
+  port = 8080
+}
+`))
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if target.Database == nil || target.Database.Host != "db.local" || len(target.Services) != 2 {
+		t.Errorf("Process() got = %+v, want all blocks decoded", target)
+	}
+	want := map[string]any{
+		"name":     "app",
+		"database": map[string]any{"host": "db.local"},
+		"service": map[string]any{
+			"api": map[string]any{"port": float64(80)},
+			"web": map[string]any{"port": float64(8080)},
+		},
+	}
+	if got := pr.RawData(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("RawData() = %v, want %v", got, want)
+	}
+}
+
+func Test_hclValues(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]any
+		wantErr bool
+	}{
+		{
+			name:    "attributes",
+			content: "name = \"app\"\nport = 8080\ndebug = true\ntags = [\"a\", \"b\"]\nlabels = { team = \"core\" }\nnone = null",
+			want: map[string]any{"name": "app", "port": float64(8080), "debug": true, "tags": []any{"a", "b"},
+				"labels": map[string]any{"team": "core"}, "none": nil},
+		},
+		{
+			name:    "repeated blocks",
+			content: "rule {\n  allow = true\n}\nrule {\n  allow = false\n}\nrule {}",
+			want:    map[string]any{"rule": []any{map[string]any{"allow": true}, map[string]any{"allow": false}, map[string]any{}}},
+		},
+		{name: "variables", content: "name = var.name", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := make(map[string]any)
+			err := decodeHCL([]byte(tc.content), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("decodeHCL() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("decodeHCL() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,191 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+)
+
+var ErrFetch = errors.New("fetching failed")
+
+type (
+	// HTTPOption configures a source added using FromHTTP.
+	HTTPOption func(hs *httpSource)
+	httpSource struct {
+		url    string
+		client *http.Client
+		// header is sent along with each request.
+		header http.Header
+		// status is the expected status code of responses; 0 means 200 OK.
+		status int
+
+		// etag and lastModified are the validators of the last successful response,
+		// sent along with subsequent requests to make them conditional.
+		etag         string
+		lastModified string
+		// content and format hold the body of the last successful response.
+		content []byte
+		format  Format
+		// fetched is the time the content last changed.
+		fetched time.Time
+	}
+)
+
+// WithHTTPHeader sets the request header key to value, e.g. for authentication.
+func WithHTTPHeader(key, value string) HTTPOption {
+	return func(hs *httpSource) {
+		hs.header.Set(key, value)
+	}
+}
+
+// WithHTTPTimeout limits the time a request may take, including reading the body.
+func WithHTTPTimeout(timeout time.Duration) HTTPOption {
+	return func(hs *httpSource) {
+		hs.client = &http.Client{Timeout: timeout}
+	}
+}
+
+// WithHTTPStatus sets the status code successful responses have, 200 OK by default.
+func WithHTTPStatus(code int) HTTPOption {
+	return func(hs *httpSource) {
+		hs.status = code
+	}
+}
+
+func (hs *httpSource) ToTarget(t any) error {
+	return decodeSource(hs, t)
+}
+
+func (hs *httpSource) describe() string {
+	return redactURL(hs.url)
+}
+
+func (hs *httpSource) load() ([]byte, Format, error) {
+	if err := hs.fetch(); err != nil {
+		return nil, "", err
+	}
+	return hs.content, hs.format, nil
+}
+
+// modTime fetches the resource, so watching picks up changes of it. The validators
+// of the previous response keep this cheap if the resource didn't change.
+func (hs *httpSource) modTime() time.Time {
+	if err := hs.fetch(); err != nil {
+		return time.Time{}
+	}
+	return hs.fetched
+}
+
+// fetch requests the resource, conditionally if it was fetched before, and stores
+// the body and validators of the response unless the server responds with 304 Not Modified.
+func (hs *httpSource) fetch() error {
+	req, err := http.NewRequest(http.MethodGet, hs.url, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range hs.header {
+		req.Header[key] = values
+	}
+	if hs.content != nil {
+		if hs.etag != "" {
+			req.Header.Set("If-None-Match", hs.etag)
+		}
+		if hs.lastModified != "" {
+			req.Header.Set("If-Modified-Since", hs.lastModified)
+		}
+	}
+	client := hs.client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrFetch, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && hs.content != nil {
+		return nil
+	}
+	status := hs.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if resp.StatusCode != status {
+		return fmt.Errorf("%w: GET %s: unexpected status %s", ErrFetch, hs.url, resp.Status)
+	}
+	format, err := formatFromResponse(resp)
+	if err != nil {
+		return err
+	}
+	cont, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: GET %s: %w", ErrFetch, hs.url, err)
+	}
+
+	hs.content, hs.format, hs.fetched = cont, format, time.Now()
+	hs.etag, hs.lastModified = resp.Header.Get("ETag"), resp.Header.Get("Last-Modified")
+	return nil
+}
+
+// formatFromResponse returns the Format of the body of resp according to its
+// Content-Type header, falling back to the extension of the requested path.
+func formatFromResponse(resp *http.Response) (Format, error) {
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil {
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			return FormatJSON, nil
+		case strings.Contains(mediaType, "yaml"):
+			return FormatYAML, nil
+		case strings.Contains(mediaType, "toml"):
+			return FormatTOML, nil
+		}
+	}
+
+	var u *url.URL
+	if resp.Request != nil {
+		u = resp.Request.URL
+	}
+	if u == nil {
+		return "", fmt.Errorf("%w: unsupported content type %q", ErrUnknownFormat, resp.Header.Get("Content-Type"))
+	}
+	return formatFromExt(path.Base(u.Path))
+}
+
+// HTTPETag returns the ETag of the last response received by the HTTP source registered
+// at sourceIndex, e.g. for diagnostics. It returns an empty string if the server didn't
+// send one or the source at sourceIndex is not an HTTP source.
+func (pr *Primordius) HTTPETag(sourceIndex int) string {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	if sourceIndex < 0 || sourceIndex >= len(pr.sources) {
+		return ""
+	}
+	if hs, ok := pr.sources[sourceIndex].(*httpSource); ok {
+		return hs.etag
+	}
+	return ""
+}
+
+// FromHTTP adds a Source to pr which fetches rawURL using a GET request and decodes the
+// body according to the Content-Type header of the response, or else the extension of
+// the URL path. Subsequent requests, e.g. when watching, are made conditional using the
+// ETag and Last-Modified headers of the previous response; if the server responds with
+// 304 Not Modified, the previous body is used without downloading it again.
+//
+// Errors of requests, including unexpected status codes, wrap ErrFetch, while errors
+// decoding the body wrap ErrDecode. See the HTTPOption functions like WithHTTPHeader
+// for options.
+func (pr *Primordius) FromHTTP(rawURL string, opts ...HTTPOption) {
+	hs := &httpSource{url: rawURL, header: make(http.Header)}
+	for _, opt := range opts {
+		opt(hs)
+	}
+	pr.AddSource(hs)
+}
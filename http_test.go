@@ -0,0 +1,175 @@
+package primordius
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestPrimordius_FromHTTP(t *testing.T) {
+	type target struct {
+		Port int `json:"port" yaml:"port" toml:"port"`
+	}
+	tests := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+		status      int
+		want        int
+		wantErr     bool
+	}{
+		{"json", "/config", "application/json; charset=utf-8", `{"port": 80}`, http.StatusOK, 80, false},
+		{"yaml", "/config", "application/yaml", "port: 81", http.StatusOK, 81, false},
+		{"toml", "/config", "application/toml", "port = 82", http.StatusOK, 82, false},
+		{"extension fallback", "/config.yaml", "text/plain", "port: 83", http.StatusOK, 83, false},
+		{"unknown format", "/config", "text/plain", "port: 84", http.StatusOK, 0, true},
+		{"unexpected status", "/config.json", "application/json", `{"port": 85}`, http.StatusNotFound, 0, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType)
+				w.WriteHeader(tc.status)
+				_, _ = w.Write([]byte(tc.body))
+			}))
+			defer srv.Close()
+
+			var got target
+			pr := New(&got)
+			pr.FromHTTP(srv.URL + tc.path)
+			err := pr.Process()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got.Port != tc.want {
+				t.Errorf("Port = %d, want %d", got.Port, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromHTTP_conditional(t *testing.T) {
+	etag, body := `"v1"`, `{"port": 80}`
+	var requests, notModified int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == etag {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(body))
+	}))
+	defer srv.Close()
+
+	var got struct {
+		Port int `json:"port"`
+	}
+	pr := New(&got)
+	pr.FromHTTP(srv.URL)
+	tests := []struct {
+		name            string
+		etag, body      string
+		wantPort        int
+		wantNotModified int
+	}{
+		{"initial", `"v1"`, `{"port": 80}`, 80, 0},
+		{"unchanged", `"v1"`, `{"port": 80}`, 80, 1},
+		{"changed", `"v2"`, `{"port": 81}`, 81, 1},
+		{"unchanged again", `"v2"`, `{"port": 81}`, 81, 2},
+	}
+	for _, tc := range tests {
+		etag, body = tc.etag, tc.body
+		got.Port = 0
+		if err := pr.Process(); err != nil {
+			t.Fatalf("%s: Process() error = %v", tc.name, err)
+		}
+		if got.Port != tc.wantPort {
+			t.Errorf("%s: Port = %d, want %d", tc.name, got.Port, tc.wantPort)
+		}
+		if notModified != tc.wantNotModified {
+			t.Errorf("%s: %d responses were 304 Not Modified, want %d", tc.name, notModified, tc.wantNotModified)
+		}
+		if pr.HTTPETag(0) != tc.etag {
+			t.Errorf("%s: HTTPETag() = %s, want %s", tc.name, pr.HTTPETag(0), tc.etag)
+		}
+	}
+	if requests != len(tests) {
+		t.Errorf("server received %d requests, want %d", requests, len(tests))
+	}
+	if pr.HTTPETag(1) != "" {
+		t.Errorf("HTTPETag() of unknown source = %q, want empty", pr.HTTPETag(1))
+	}
+}
+
+func TestPrimordius_FromHTTP_options(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Header.Get("Authorization") != "Bearer t0ken":
+			w.WriteHeader(http.StatusUnauthorized)
+		case r.URL.Path == "/slow":
+			time.Sleep(200 * time.Millisecond)
+			fallthrough
+		case r.URL.Path == "/config":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"port": 80}`))
+		case r.URL.Path == "/accepted":
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte(`{"port": 81}`))
+		case r.URL.Path == "/invalid":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"port": `))
+		}
+	}))
+	defer srv.Close()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+
+	auth := WithHTTPHeader("Authorization", "Bearer t0ken")
+	tests := []struct {
+		name    string
+		url     string
+		opts    []HTTPOption
+		want    int
+		wantErr error
+	}{
+		{"header", srv.URL + "/config", []HTTPOption{auth}, 80, nil},
+		{"missing header", srv.URL + "/config", nil, 0, ErrFetch},
+		{"expected status", srv.URL + "/accepted", []HTTPOption{auth, WithHTTPStatus(http.StatusAccepted)}, 81, nil},
+		{"unexpected status", srv.URL + "/accepted", []HTTPOption{auth}, 0, ErrFetch},
+		{"timeout", srv.URL + "/slow", []HTTPOption{auth, WithHTTPTimeout(50 * time.Millisecond)}, 0, ErrFetch},
+		{"within timeout", srv.URL + "/slow", []HTTPOption{auth, WithHTTPTimeout(5 * time.Second)}, 80, nil},
+		{"network failure", closed.URL + "/config", nil, 0, ErrFetch},
+		{"decode failure", srv.URL + "/invalid", []HTTPOption{auth}, 0, ErrDecode},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got struct {
+				Port int `json:"port"`
+			}
+			pr := New(&got)
+			pr.FromHTTP(tc.url, tc.opts...)
+			err := pr.Process()
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("Process() error = %v, want nil", err)
+			case tc.wantErr != nil && !errors.Is(err, tc.wantErr):
+				t.Fatalf("Process() error = %v, want %v", err, tc.wantErr)
+			}
+			if errors.Is(err, ErrFetch) && errors.Is(err, ErrDecode) {
+				t.Errorf("Process() error = %v matches both ErrFetch and ErrDecode", err)
+			}
+			if got.Port != tc.want {
+				t.Errorf("Port = %d, want %d", got.Port, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var ErrModified = errors.New("target was modified after processing")
+
+// AssertUnchanged returns an error wrapping ErrModified and naming the affected
+// fields if the target was modified after the last successful call to Process or
+// ProcessFields, compared to a copy of its values taken right afterwards. This
+// catches code accidentally mutating shared configuration at runtime. Only exported
+// fields are taken into account.
+func (pr *Primordius) AssertUnchanged() error {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	if pr.fingerprint == nil {
+		return ErrNotProcessed
+	}
+
+	current, err := takeSnapshot(pr.target)
+	if err != nil {
+		return err
+	}
+	changed := current.changed(pr.fingerprint)
+	for path := range pr.fingerprint {
+		if _, ok := current[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	if len(changed) > 0 {
+		sort.Strings(changed)
+		return fmt.Errorf("%w: %s", ErrModified, strings.Join(changed, ", "))
+	}
+
+	return nil
+}
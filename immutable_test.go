@@ -0,0 +1,39 @@
+package primordius
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrimordius_AssertUnchanged(t *testing.T) {
+	type limits struct {
+		Max int `yaml:"max"`
+	}
+	var target struct {
+		Hosts  []string `yaml:"hosts"`
+		Limits *limits  `yaml:"limits"`
+	}
+
+	pr := New(&target)
+	if err := pr.AssertUnchanged(); err != ErrNotProcessed {
+		t.Errorf("AssertUnchanged() error = %v, want %v", err, ErrNotProcessed)
+	}
+
+	pr.FromYAML([]byte("hosts: [a, b]\nlimits:\n  max: 3"))
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if err := pr.AssertUnchanged(); err != nil {
+		t.Errorf("AssertUnchanged() error = %v, want nil", err)
+	}
+
+	target.Hosts[1] = "c"
+	target.Limits.Max = 4
+	err := pr.AssertUnchanged()
+	if !errors.Is(err, ErrModified) {
+		t.Fatalf("AssertUnchanged() error = %v, want %v", err, ErrModified)
+	}
+	if want := "target was modified after processing: Hosts, Limits.Max"; err.Error() != want {
+		t.Errorf("AssertUnchanged() error = %q, want %q", err, want)
+	}
+}
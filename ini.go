@@ -0,0 +1,270 @@
+package primordius
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatINI  Format = "ini"
+	iniTagName        = "ini"
+)
+
+type (
+	iniFileSource struct {
+		name string
+	}
+	iniContentSource struct {
+		content []byte
+	}
+	iniReaderSource struct {
+		r io.Reader
+	}
+	// iniSections maps section names to their keys and values. Keys before the first
+	// section header belong to the global section "".
+	iniSections map[string]map[string]string
+)
+
+func init() {
+	RegisterCodec(FormatINI, decodeINI)
+}
+
+func (is *iniFileSource) ToTarget(t any) error {
+	return decodeSource(is, t)
+}
+
+func (is *iniFileSource) describe() string {
+	return is.name
+}
+
+func (is *iniFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(is.name)
+	return cont, FormatINI, err
+}
+
+func (is *iniFileSource) modTime() time.Time { return fileModTime(is.name) }
+
+func (is *iniContentSource) ToTarget(t any) error {
+	return decodeSource(is, t)
+}
+
+func (is *iniContentSource) load() ([]byte, Format, error) {
+	return is.content, FormatINI, nil
+}
+
+func (is *iniReaderSource) ToTarget(t any) error {
+	return decodeSource(is, t)
+}
+
+func (is *iniReaderSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(is.r)
+	return cont, FormatINI, err
+}
+
+// decodeINI decodes INI content into t, which points to a struct or a map[string]any.
+// Keys of the global section are assigned to the top-level fields of the struct, and
+// each section to the struct field it names; dotted section names such as
+// [database.replica] address nested structs. Fields are matched by their ini tag,
+// else the key of their env tag, else their name, case-insensitively. Values are
+// converted like those of environment variables.
+func decodeINI(content []byte, t any) error {
+	sections, err := parseINI(string(content))
+	if err != nil {
+		return err
+	}
+
+	return sections.decode(t, iniTagName)
+}
+
+// decode assigns the sections to t, which points to a struct or a map[string]any.
+// Struct fields are matched by tag, else the key of their env tag, else their name.
+func (is iniSections) decode(t any, tag string) error {
+	if m, ok := t.(*map[string]any); ok {
+		if *m == nil {
+			*m = make(map[string]any)
+		}
+		for name, keys := range is {
+			dst := *m
+			if name != "" {
+				sub := make(map[string]any, len(keys))
+				dst[name] = sub
+				dst = sub
+			}
+			for k, v := range keys {
+				dst[k] = v
+			}
+		}
+		return nil
+	}
+
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+	return is.apply(v.Elem(), "", tag)
+}
+
+// apply assigns the keys of the section named section to the fields of the struct s
+// and the sections nested in it to its struct fields.
+func (is iniSections) apply(s reflect.Value, section, tag string) error {
+	keys := is.section(section)
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := sectionKey(sf, tag)
+		if name == "-" {
+			continue
+		}
+		f := s.Field(i)
+
+		ft := sf.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if hasExportedFields(ft) && !convertible(sf.Type) {
+			sub := name
+			if section != "" {
+				sub = section + "." + name
+			}
+			if !is.contains(sub) {
+				continue
+			}
+			if f.Kind() == reflect.Pointer {
+				if f.IsNil() {
+					f.Set(reflect.New(ft))
+				}
+				f = f.Elem()
+			}
+			if err := is.apply(f, sub, tag); err != nil {
+				return err
+			}
+			continue
+		}
+
+		val, ok := lookupFold(keys, name)
+		if !ok {
+			continue
+		}
+		if err := setValue(f, val); err != nil {
+			if section != "" {
+				return fmt.Errorf("section %s, key %s: %w", section, name, err)
+			}
+			return fmt.Errorf("key %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// section returns the keys of the section name, matched case-insensitively.
+func (is iniSections) section(name string) map[string]string {
+	keys, _ := lookupFold(is, name)
+	return keys
+}
+
+// lookupFold returns the value of m for key, preferring an exact match over one
+// differing in case only.
+func lookupFold[V any](m map[string]V, key string) (V, bool) {
+	if v, ok := m[key]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// contains reports whether there is a section named name or nested in it.
+func (is iniSections) contains(name string) bool {
+	for n := range is {
+		if strings.EqualFold(n, name) || len(n) > len(name) && strings.EqualFold(n[:len(name)+1], name+".") {
+			return true
+		}
+	}
+	return false
+}
+
+// sectionKey returns the name matching the field sf in INI or properties content,
+// taken from the tag named tag if present.
+func sectionKey(sf reflect.StructField, tag string) string {
+	if name := sf.Tag.Get(tag); name != "" {
+		return name
+	}
+	if key, _ := parseTag(sf.Tag.Get(tagName)); key != "" && !strings.HasSuffix(key, "*") {
+		return key
+	}
+	return sf.Name
+}
+
+// parseINI parses INI content. Lines starting with ; or # are comments. Keys and
+// values are separated by = or :, and double-quoted values may contain Go escape
+// sequences. Values which are not quoted end at a ; or # preceded by whitespace.
+func parseINI(content string) (iniSections, error) {
+	sections := iniSections{"": make(map[string]string)}
+	section := ""
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || line[0] == ';' || line[0] == '#' {
+			continue
+		}
+		if line[0] == '[' {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("ini: line %d: unterminated section header", i+1)
+			}
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			if sections[section] == nil {
+				sections[section] = make(map[string]string)
+			}
+			continue
+		}
+
+		sep := strings.IndexAny(line, "=:")
+		if sep <= 0 {
+			return nil, fmt.Errorf("ini: line %d: expected key = value", i+1)
+		}
+		key, val := strings.TrimSpace(line[:sep]), strings.TrimSpace(line[sep+1:])
+		if strings.HasPrefix(val, `"`) {
+			quoted, err := strconv.QuotedPrefix(val)
+			if err != nil {
+				return nil, fmt.Errorf("ini: line %d: %w", i+1, err)
+			}
+			if val, err = strconv.Unquote(quoted); err != nil {
+				return nil, fmt.Errorf("ini: line %d: %w", i+1, err)
+			}
+		} else if end := strings.IndexAny(val, ";#"); end > 0 && (val[end-1] == ' ' || val[end-1] == '\t') {
+			val = strings.TrimSpace(val[:end])
+		}
+		sections[section][key] = val
+	}
+
+	return sections, nil
+}
+
+// FromINIFile adds a Source to pr which reads values from an INI file. Keys before the
+// first section are assigned to top-level fields, sections to the struct fields they
+// name. Fields are matched by their ini tag, else the key of their env tag, else their
+// name, case-insensitively.
+func (pr *Primordius) FromINIFile(name string) {
+	pr.AddSource(&iniFileSource{name: name})
+}
+
+// FromINI adds a Source to pr which reads values from an INI block.
+func (pr *Primordius) FromINI(content []byte) {
+	pr.AddSource(&iniContentSource{content: content})
+}
+
+// FromINIReader adds a Source to pr which reads INI content from r.
+func (pr *Primordius) FromINIReader(r io.Reader) {
+	pr.AddSource(&iniReaderSource{r: r})
+}
@@ -0,0 +1,128 @@
+package primordius
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseINI(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    iniSections
+		wantErr bool
+	}{
+		{
+			name:    "global and sections",
+			content: "; comment\nname = app\n\n[database]\nhost: db.local # primary\nport=5432\r\n[database.replica]\nhost = \"replica;1\"",
+			want: iniSections{
+				"":                 {"name": "app"},
+				"database":         {"host": "db.local", "port": "5432"},
+				"database.replica": {"host": "replica;1"},
+			},
+		},
+		{
+			name:    "comment characters within values",
+			content: "url = http://host/#anchor\nratio=1;2",
+			want:    iniSections{"": {"url": "http://host/#anchor", "ratio": "1;2"}},
+		},
+		{name: "unterminated section", content: "[database", wantErr: true},
+		{name: "missing value", content: "[database]\nhost", wantErr: true},
+		{name: "invalid quoted value", content: `host = "db`, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseINI(tc.content)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseINI() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseINI() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_iniSources(t *testing.T) {
+	type replica struct {
+		Host string `ini:"host"`
+	}
+	type database struct {
+		Host    string        `ini:"host"`
+		Port    int           `env:"PORT"`
+		Timeout time.Duration `ini:"timeout"`
+		Replica *replica      `ini:"replica"`
+	}
+	type target struct {
+		Name     string   `ini:"name"`
+		Debug    bool     `ini:"-"`
+		Tags     []string `ini:"tags"`
+		Database database `ini:"database"`
+		Cache    *database
+	}
+	content := `name = app
+debug = true
+tags = a,b
+
+[DATABASE]
+host = db.local
+port = 5432
+timeout = 5s
+
+[database.replica]
+host = replica.local
+`
+	want := target{Name: "app", Tags: []string{"a", "b"},
+		Database: database{Host: "db.local", Port: 5432, Timeout: 5 * time.Second, Replica: &replica{Host: "replica.local"}}}
+	name := filepath.Join(t.TempDir(), "config.ini")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{"file", &iniFileSource{name: name}, false},
+		{"content", &iniContentSource{content: []byte(content)}, false},
+		{"reader", &iniReaderSource{r: strings.NewReader(content)}, false},
+		{"generic file", &fileSource{name: name}, false},
+		{"missing file", &iniFileSource{name: name + ".missing"}, true},
+		{"invalid value", &iniContentSource{content: []byte("[database]\nport = x")}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := tc.source.ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromINI_rawData(t *testing.T) {
+	var target struct {
+		Name string `ini:"name"`
+	}
+	pr := New(&target)
+	pr.SetCaptureRawData(true)
+	pr.FromINI([]byte("name = app\n[plugins]\nauth = ldap"))
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	want := map[string]any{"name": "app", "plugins": map[string]any{"auth": "ldap"}}
+	if got := pr.RawData(0); !reflect.DeepEqual(got, want) {
+		t.Errorf("RawData() = %v, want %v", got, want)
+	}
+}
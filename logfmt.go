@@ -0,0 +1,69 @@
+package primordius
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// setLogfmt parses val as logfmt-style key=value pairs, e.g. `host=db.local port=5432`,
+// and assigns them to the fields of the struct, or pointer to struct, f by their env tags.
+func (c *converter) setLogfmt(f reflect.Value, path, val string) error {
+	f, err := structTarget(f, path, "logfmt")
+	if err != nil {
+		return err
+	}
+
+	pairs, err := parseLogfmt(val)
+	if err != nil {
+		return fmt.Errorf("field %s: %w", path, err)
+	}
+	if err := c.applyTagged(f.Addr().Interface(), pairs); err != nil {
+		return fmt.Errorf("field %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// parseLogfmt splits s into logfmt key=value pairs separated by whitespace. Values
+// containing whitespace can be double-quoted using Go escape sequences. A key without
+// a value, e.g. "debug", is treated as "debug=true".
+func parseLogfmt(s string) (valueMap, error) {
+	pairs := make(valueMap)
+	for s = strings.TrimSpace(s); s != ""; s = strings.TrimSpace(s) {
+		end := strings.IndexAny(s, " \t=")
+		if end == -1 {
+			end = len(s)
+		}
+		key := s[:end]
+		if key == "" {
+			return nil, fmt.Errorf("logfmt: missing key before %q", s)
+		}
+		s = s[end:]
+		if !strings.HasPrefix(s, "=") {
+			pairs[key] = "true"
+			continue
+		}
+		s = s[1:]
+
+		if !strings.HasPrefix(s, `"`) {
+			end = strings.IndexAny(s, " \t")
+			if end == -1 {
+				end = len(s)
+			}
+			pairs[key], s = s[:end], s[end:]
+			continue
+		}
+		quoted, err := strconv.QuotedPrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("logfmt: value of key %s: %w", key, err)
+		}
+		if pairs[key], err = strconv.Unquote(quoted); err != nil {
+			return nil, fmt.Errorf("logfmt: value of key %s: %w", key, err)
+		}
+		s = s[len(quoted):]
+	}
+
+	return pairs, nil
+}
@@ -0,0 +1,77 @@
+package primordius
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_parseLogfmt(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    valueMap
+		wantErr bool
+	}{
+		{"empty", "  ", valueMap{}, false},
+		{"pairs", "host=db.local port=5432", valueMap{"host": "db.local", "port": "5432"}, false},
+		{"quoted", `name="my db" path="C:\\data" tls=true`, valueMap{"name": "my db", "path": `C:\data`, "tls": "true"}, false},
+		{"empty value", "user= pass=x", valueMap{"user": "", "pass": "x"}, false},
+		{"bare key", "debug \tport=1", valueMap{"debug": "true", "port": "1"}, false},
+		{"missing key", "=x", nil, true},
+		{"unterminated quote", `name="my db`, nil, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseLogfmt(tc.in)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseLogfmt() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseLogfmt() = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_envSource_ToTarget_logfmt(t *testing.T) {
+	type database struct {
+		Host string `env:"host"`
+		Port int    `env:"port"`
+		Name string `env:"name"`
+	}
+	if err := os.Setenv("LF_DB", `host=db.local port=5432 name="my db"`); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("LF_DB")
+
+	var target struct {
+		DB      database  `env:"DB,logfmt"`
+		Replica *database `env:"DB,logfmt"`
+	}
+	target.DB.Port = 1
+	if err := (&envSource{prefix: "LF_"}).ToTarget(&target); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+	want := database{Host: "db.local", Port: 5432, Name: "my db"}
+	if target.DB != want {
+		t.Errorf("DB = %+v, want %+v", target.DB, want)
+	}
+	if target.Replica == nil || *target.Replica != want {
+		t.Errorf("Replica = %+v, want %+v", target.Replica, want)
+	}
+
+	if err := os.Setenv("LF_DB", "port=high"); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&envSource{prefix: "LF_"}).ToTarget(&target); err == nil {
+		t.Error("ToTarget() error = nil, want error for invalid value")
+	}
+	var notStruct struct {
+		DB string `env:"DB,logfmt"`
+	}
+	if err := (&envSource{prefix: "LF_"}).ToTarget(&notStruct); err == nil {
+		t.Error("ToTarget() error = nil, want error for non-struct field")
+	}
+}
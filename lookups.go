@@ -0,0 +1,62 @@
+package primordius
+
+import "sort"
+
+// EnvLookup describes the attempt of an env source to read the value of a field.
+type EnvLookup struct {
+	// Source is the index of the env source.
+	Source int
+	// Field is the path of the field, e.g. "Port".
+	Field string
+	// Key is the fully composed name of the environment variable, prefix included.
+	Key string
+	// Found reports whether the environment variable was set.
+	Found bool
+}
+
+// EnvLookups returns, in processing order, the environment variables the env sources
+// of pr attempted to read during the last call to Process and whether they were set.
+// This helps finding out which exact variable a field is read from.
+func (pr *Primordius) EnvLookups() []EnvLookup {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	lookups := make([]EnvLookup, 0)
+	for i, s := range pr.sources {
+		es, ok := s.(*envSource)
+		if !ok {
+			continue
+		}
+		for _, l := range es.lookups {
+			l.Source = i
+			lookups = append(lookups, l)
+		}
+	}
+
+	return lookups
+}
+
+// EnvChanges returns the sorted paths of the fields whose environment variables changed
+// their values, or were set or unset, in the last call to Process compared to the call
+// before. In the first call, all fields found in the environment are reported. This
+// allows reacting precisely to changes when reloading, e.g. using WatchContext.
+func (pr *Primordius) EnvChanges() []string {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	seen := make(map[string]bool)
+	fields := make([]string, 0)
+	for _, s := range pr.sources {
+		es, ok := s.(*envSource)
+		if !ok {
+			continue
+		}
+		for _, field := range es.changed {
+			if !seen[field] {
+				seen[field] = true
+				fields = append(fields, field)
+			}
+		}
+	}
+	sort.Strings(fields)
+
+	return fields
+}
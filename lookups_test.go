@@ -0,0 +1,169 @@
+package primordius
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestPrimordius_EnvLookups(t *testing.T) {
+	var target struct {
+		Host string `env:"HOST"`
+		Port int    `env:"PORT"`
+		Name string
+	}
+	if err := os.Setenv("LOOKUP_HOST", "example.com"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("LOOKUP_HOST")
+
+	pr := New(&target)
+	pr.FromYAML([]byte("name: app"))
+	pr.FromEnv("LOOKUP_")
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	want := []EnvLookup{
+		{Source: 1, Field: "Host", Key: "LOOKUP_HOST", Found: true},
+		{Source: 1, Field: "Port", Key: "LOOKUP_PORT", Found: false},
+	}
+	if got := pr.EnvLookups(); !reflect.DeepEqual(got, want) {
+		t.Errorf("EnvLookups() = %+v, want %+v", got, want)
+	}
+}
+
+func TestPrimordius_EnvChanges(t *testing.T) {
+	var target struct {
+		Host   string            `env:"HOST"`
+		Port   int               `env:"PORT"`
+		Debug  bool              `env:"DEBUG"`
+		Labels map[string]string `env:"LABEL_*"`
+	}
+	env := map[string]string{"CHG_HOST": "example.com", "CHG_PORT": "80", "CHG_LABEL_team": "a"}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+
+	pr := New(&target)
+	pr.FromEnv("CHG_")
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if want := []string{"Host", "Labels", "Port"}; !reflect.DeepEqual(pr.EnvChanges(), want) {
+		t.Errorf("EnvChanges() after first run = %v, want %v", pr.EnvChanges(), want)
+	}
+
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if len(pr.EnvChanges()) != 0 {
+		t.Errorf("EnvChanges() without changes = %v, want none", pr.EnvChanges())
+	}
+
+	if err := os.Setenv("CHG_PORT", "8080"); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Setenv("CHG_DEBUG", "true"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("CHG_DEBUG")
+	if err := os.Unsetenv("CHG_LABEL_team"); err != nil {
+		t.Fatal(err)
+	}
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if want := []string{"Debug", "Labels", "Port"}; !reflect.DeepEqual(pr.EnvChanges(), want) {
+		t.Errorf("EnvChanges() after change = %v, want %v", pr.EnvChanges(), want)
+	}
+}
+
+func TestPrimordius_SetEnvSeparator(t *testing.T) {
+	env := map[string]string{"SEPAPP_PORT": "1", "SEPAPPPORT": "2", "SEPAPP__PORT": "3"}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+
+	tests := []struct {
+		name   string
+		prefix string
+		sep    *string
+		want   int
+	}{
+		{"default separator", "SEPAPP", nil, 1},
+		{"prefix ending with separator", "SEPAPP_", nil, 1},
+		{"literal concatenation", "SEPAPP", strPtr(""), 2},
+		{"custom separator", "SEPAPP", strPtr("__"), 3},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var target struct {
+				Port int `env:"PORT"`
+			}
+			pr := New(&target)
+			if tc.sep != nil {
+				pr.SetEnvSeparator(*tc.sep)
+			}
+			pr.FromEnv(tc.prefix)
+			if err := pr.Process(); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if target.Port != tc.want {
+				t.Errorf("Port = %d, want %d", target.Port, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_SetEnvAllowlist(t *testing.T) {
+	env := map[string]string{"ALLOW_PORT": "8080", "ALLOW_HOST": "example.com", "ALLOW_EXTRA_A": "a"}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			t.Fatal(err)
+		}
+		defer os.Unsetenv(k)
+	}
+
+	type target struct {
+		Port  int               `env:"PORT"`
+		Host  string            `env:"HOST"`
+		Extra map[string]string `env:"EXTRA_*"`
+	}
+	tests := []struct {
+		name      string
+		allowlist []string
+		want      target
+	}{
+		{"no allowlist", nil, target{Port: 8080, Host: "example.com", Extra: map[string]string{"A": "a"}}},
+		{"allowlisted subset", []string{"ALLOW_PORT", "ALLOW_EXTRA_A"}, target{Port: 8080, Extra: map[string]string{"A": "a"}}},
+		{"names without prefix", []string{"PORT", "HOST"}, target{}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.SetEnvAllowlist(tc.allowlist...)
+			pr.FromEnv("ALLOW")
+			if err := pr.Process(); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+			for _, l := range pr.EnvLookups() {
+				if tc.allowlist != nil && l.Key != "ALLOW_PORT" && l.Key != "ALLOW_EXTRA_A" {
+					t.Errorf("EnvLookups() contains %s, which is not allowlisted", l.Key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package primordius
+
+import (
+	"reflect"
+	"sort"
+)
+
+type (
+	// MapConflict describes a key of a map[string]any field whose value was replaced
+	// by a source while merging maps deeply.
+	MapConflict struct {
+		// Key is the dotted path of the key, starting with the path of the field,
+		// e.g. "Extra.database.host".
+		Key string
+		// Source is the index of the source which set the winning value.
+		Source int
+		// Overridden is the index of the source which set the replaced value, or -1
+		// if the value was present in the target before processing.
+		Overridden int
+	}
+	// mapMerge holds the state of merging map fields during Process.
+	mapMerge struct {
+		// origins maps the path of a key to the index of the source which set it last.
+		origins   map[string]int
+		conflicts []MapConflict
+	}
+)
+
+var anyMapType = reflect.TypeOf(map[string]any(nil))
+
+// SetDeepMergeMaps controls whether fields of type map[string]any are merged deeply
+// across sources. By default, the top-level keys provided by a source are added to
+// the map, but nested maps are replaced as a whole; if enabled, nested maps are merged
+// key by key as well, and only the values of keys present in both are replaced.
+// Keys whose values were replaced are reported by MapConflicts.
+func (pr *Primordius) SetDeepMergeMaps(enabled bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.deepMergeMaps = enabled
+}
+
+// MapConflicts returns the keys of map[string]any fields whose values were replaced
+// by another source during the last call to Process, sorted by source and key. It
+// returns nil unless deep merging is enabled using SetDeepMergeMaps.
+func (pr *Primordius) MapConflicts() []MapConflict {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	if pr.mapMerge == nil {
+		return nil
+	}
+	return pr.mapMerge.conflicts
+}
+
+// takeMaps sets all map[string]any fields of the struct target points to to nil,
+// so a source decodes into empty maps, and returns their previous values by path.
+func takeMaps(target any) map[string]map[string]any {
+	maps := make(map[string]map[string]any)
+	v := reflect.ValueOf(target)
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		collectMaps(v.Elem(), "", maps)
+	}
+	return maps
+}
+
+func collectMaps(s reflect.Value, path string, maps map[string]map[string]any) {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		fieldPath := t.Field(i).Name
+		if path != "" {
+			fieldPath = path + "." + fieldPath
+		}
+		f := s.Field(i)
+		if f.Kind() == reflect.Pointer && !f.IsNil() {
+			f = f.Elem()
+		}
+		switch {
+		case f.Kind() == reflect.Struct:
+			collectMaps(f, fieldPath, maps)
+		case f.Type() == anyMapType && f.CanSet():
+			maps[fieldPath], _ = f.Interface().(map[string]any)
+			f.Set(reflect.Zero(anyMapType))
+		}
+	}
+}
+
+// merge merges the maps decoded into target by the source at index i into the
+// previous values returned by takeMaps and assigns the results to the fields.
+func (mm *mapMerge) merge(target any, i int, previous map[string]map[string]any) error {
+	paths := make([]string, 0, len(previous))
+	for path := range previous {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		f, err := fieldByPath(reflect.ValueOf(target).Elem(), path, true)
+		if err != nil {
+			return err
+		}
+		merged := previous[path]
+		if current, _ := f.Interface().(map[string]any); current != nil {
+			if merged == nil {
+				merged = make(map[string]any, len(current))
+			}
+			mm.mergeInto(merged, normalizeMap(current), path, i)
+		}
+		f.Set(reflect.ValueOf(merged))
+	}
+
+	return nil
+}
+
+// mergeInto merges src, provided by the source at index i, into dst recursively.
+func (mm *mapMerge) mergeInto(dst, src map[string]any, path string, i int) {
+	keys := make([]string, 0, len(src))
+	for key := range src {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		keyPath := path + "." + key
+		val := src[key]
+		if sm, ok := val.(map[string]any); ok {
+			if dm, ok := dst[key].(map[string]any); ok {
+				mm.mergeInto(dm, sm, keyPath, i)
+				continue
+			}
+		}
+		if old, exists := dst[key]; exists && !reflect.DeepEqual(old, val) {
+			overridden, ok := mm.origins[keyPath]
+			if !ok {
+				overridden = -1
+			}
+			mm.conflicts = append(mm.conflicts, MapConflict{Key: keyPath, Source: i, Overridden: overridden})
+		}
+		dst[key] = val
+		mm.setOrigin(keyPath, val, i)
+	}
+}
+
+// setOrigin records the source at index i as origin of the key at path and, if val
+// is a map, of all its nested keys.
+func (mm *mapMerge) setOrigin(path string, val any, i int) {
+	mm.origins[path] = i
+	if m, ok := val.(map[string]any); ok {
+		for key, v := range m {
+			mm.setOrigin(path+"."+key, v, i)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package primordius
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrimordius_SetDeepMergeMaps(t *testing.T) {
+	type nested struct {
+		Labels map[string]any `yaml:"labels" json:"labels"`
+	}
+	type target struct {
+		Extra  map[string]any `yaml:"extra" json:"extra"`
+		Nested *nested        `yaml:"nested" json:"nested"`
+	}
+
+	tests := []struct {
+		name          string
+		enabled       bool
+		want          target
+		wantConflicts []MapConflict
+	}{
+		{
+			name:    "replace by default",
+			enabled: false,
+			want: target{
+				Extra:  map[string]any{"db": map[string]any{"host": "db.prod"}, "cache": "redis", "debug": true},
+				Nested: &nested{Labels: map[string]any{"team": "b", "tier": "web"}},
+			},
+		},
+		{
+			name:    "deep merge",
+			enabled: true,
+			want: target{
+				Extra: map[string]any{
+					"db":    map[string]any{"host": "db.prod", "port": 5432},
+					"cache": "redis",
+					"debug": true,
+				},
+				Nested: &nested{Labels: map[string]any{"team": "b", "tier": "web"}},
+			},
+			wantConflicts: []MapConflict{
+				{Key: "Extra.db.host", Source: 1, Overridden: 0},
+				{Key: "Nested.Labels.team", Source: 1, Overridden: -1},
+				{Key: "Extra.debug", Source: 2, Overridden: 1},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got := target{Nested: &nested{Labels: map[string]any{"team": "a", "tier": "web"}}}
+			pr := New(&got)
+			pr.SetDeepMergeMaps(tc.enabled)
+			pr.FromYAML([]byte("extra:\n  db:\n    host: localhost\n    port: 5432\n  cache: redis"))
+			pr.FromJSON([]byte(`{"extra": {"db": {"host": "db.prod"}, "debug": false}, "nested": {"labels": {"team": "b"}}}`))
+			pr.FromYAML([]byte("extra:\n  debug: true"))
+
+			if err := pr.Process(); err != nil {
+				t.Fatalf("Process() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, %+v, want %+v, %+v", got, got.Nested, tc.want, tc.want.Nested)
+			}
+			if !reflect.DeepEqual(pr.MapConflicts(), tc.wantConflicts) {
+				t.Errorf("MapConflicts() = %+v, want %+v", pr.MapConflicts(), tc.wantConflicts)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+package primordius
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+)
+
+type mergePatchEnvSource struct {
+	key string
+}
+
+func (ms *mergePatchEnvSource) ToTarget(t any) error {
+	val, exists := os.LookupEnv(ms.key)
+	if !exists {
+		return nil
+	}
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	var patch map[string]any
+	if err := json.Unmarshal([]byte(val), &patch); err != nil {
+		return fmt.Errorf("env var %s: merge patch must be a JSON object: %w", ms.key, err)
+	}
+	cont, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	var doc any
+	if err := json.Unmarshal(cont, &doc); err != nil {
+		return err
+	}
+	cont, err = json.Marshal(mergePatch(doc, patch))
+	if err != nil {
+		return err
+	}
+
+	// decode into a fresh value so removed members end up as zero values, then
+	// replace only the fields the patch refers to, leaving all others untouched
+	patched := reflect.New(v.Elem().Type())
+	if err := decode(FormatJSON, cont, patched.Interface()); err != nil {
+		return fmt.Errorf("env var %s: applying merge patch: %w", ms.key, err)
+	}
+	st := v.Elem().Type()
+	for i := 0; i < st.NumField(); i++ {
+		if !st.Field(i).IsExported() {
+			continue
+		}
+		if _, ok := rawKey(patch, st.Field(i), FormatJSON); ok {
+			v.Elem().Field(i).Set(patched.Elem().Field(i))
+		}
+	}
+
+	return nil
+}
+
+func (ms *mergePatchEnvSource) describe() string {
+	return ms.key
+}
+
+// mergePatch applies patch to target as defined by RFC 7386 and returns the result.
+// Members of patch with a null value are removed from target.
+func mergePatch(target, patch any) any {
+	p, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+	t, ok := target.(map[string]any)
+	if !ok {
+		t = make(map[string]any, len(p))
+	}
+	for k, v := range p {
+		if v == nil {
+			delete(t, k)
+			continue
+		}
+		t[k] = mergePatch(t[k], v)
+	}
+
+	return t
+}
+
+// FromJSONMergePatchEnv adds a Source to pr which reads a JSON merge patch (RFC 7386)
+// from the environment variable key and applies it to the target as populated by
+// the sources added before, e.g.
+//
+//	MY_APP_PATCH='{"server": {"port": 8443}, "debug": null}'
+//
+// sets only the server port and resets the debug field to its zero value. The target
+// is serialized using its json tags. If the variable is not set, the source does nothing.
+func (pr *Primordius) FromJSONMergePatchEnv(key string) {
+	pr.AddSource(&mergePatchEnvSource{key: key})
+}
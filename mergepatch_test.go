@@ -0,0 +1,59 @@
+package primordius
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_mergePatchEnvSource_ToTarget(t *testing.T) {
+	type server struct {
+		Host string `json:"host"`
+		Port int    `json:"port"`
+	}
+	type target struct {
+		Server server   `json:"server"`
+		Debug  bool     `json:"debug"`
+		Tags   []string `json:"tags"`
+		Name   string   `json:"-"`
+	}
+	initial := target{Server: server{Host: "localhost", Port: 80}, Debug: true, Tags: []string{"a"}, Name: "app"}
+
+	tests := []struct {
+		name    string
+		value   *string
+		want    target
+		wantErr bool
+	}{
+		{"unset", nil, initial, false},
+		{"nested member", strPtr(`{"server": {"port": 8443}}`),
+			target{Server: server{Host: "localhost", Port: 8443}, Debug: true, Tags: []string{"a"}, Name: "app"}, false},
+		{"null removes member", strPtr(`{"debug": null, "server": {"host": null}}`),
+			target{Server: server{Port: 80}, Tags: []string{"a"}, Name: "app"}, false},
+		{"arrays are replaced", strPtr(`{"tags": ["b", "c"]}`),
+			target{Server: server{Host: "localhost", Port: 80}, Debug: true, Tags: []string{"b", "c"}, Name: "app"}, false},
+		{"not an object", strPtr(`["debug"]`), initial, true},
+		{"type mismatch", strPtr(`{"server": {"port": "high"}}`), initial, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			const key = "PRIMORDIUS_TEST_PATCH"
+			if tc.value != nil {
+				if err := os.Setenv(key, *tc.value); err != nil {
+					t.Fatal(err)
+				}
+				defer os.Unsetenv(key)
+			}
+
+			got := initial
+			got.Tags = append([]string{}, initial.Tags...)
+			if err := (&mergePatchEnvSource{key: key}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
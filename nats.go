@@ -0,0 +1,63 @@
+package primordius
+
+import (
+	"context"
+	"fmt"
+)
+
+type (
+	// NATSKeyValue is the subset of a NATS JetStream key-value client required by the
+	// NATS source. Wrap your client of choice to satisfy it, e.g. for nats.go:
+	//
+	//	func (a adapter) Get(ctx context.Context, bucket, key string) ([]byte, error) {
+	//		kv, err := a.js.KeyValue(bucket)
+	//		if err != nil {
+	//			return nil, err
+	//		}
+	//		entry, err := kv.Get(key)
+	//		if err != nil {
+	//			return nil, err
+	//		}
+	//		return entry.Value(), nil
+	//	}
+	NATSKeyValue interface {
+		// Keys returns all keys in bucket.
+		Keys(ctx context.Context, bucket string) ([]string, error)
+		// Get returns the value stored at key in bucket.
+		Get(ctx context.Context, bucket, key string) ([]byte, error)
+	}
+	natsKVSource struct {
+		kv     NATSKeyValue
+		bucket string
+		conv   *converter
+	}
+)
+
+func (ns *natsKVSource) ToTarget(t any) error {
+	ctx := context.Background()
+	keys, err := ns.kv.Keys(ctx, ns.bucket)
+	if err != nil {
+		return fmt.Errorf("listing keys of NATS bucket %s: %w", ns.bucket, err)
+	}
+	values := make(valueMap, len(keys))
+	for _, key := range keys {
+		val, err := ns.kv.Get(ctx, ns.bucket, key)
+		if err != nil {
+			return fmt.Errorf("reading key %s of NATS bucket %s: %w", key, ns.bucket, err)
+		}
+		values[key] = string(val)
+	}
+
+	return ns.conv.applyTagged(t, values)
+}
+
+func (ns *natsKVSource) describe() string {
+	return ns.bucket
+}
+
+// FromNATSKV adds a Source to pr which reads all keys of a NATS JetStream key-value
+// bucket. Keys are matched against the 'env' tag and values are converted like those
+// of environment variables.
+func (pr *Primordius) FromNATSKV(kv NATSKeyValue, bucket string) {
+	pr.AddSource(&natsKVSource{kv: kv, bucket: bucket, conv: pr.conv})
+}
@@ -0,0 +1,66 @@
+package primordius
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+)
+
+type fakeNATSKV map[string]map[string]string
+
+func (fk fakeNATSKV) Keys(_ context.Context, bucket string) ([]string, error) {
+	b, ok := fk[bucket]
+	if !ok {
+		return nil, errors.New("nats: bucket not found")
+	}
+	keys := make([]string, 0, len(b))
+	for key := range b {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+func (fk fakeNATSKV) Get(_ context.Context, bucket, key string) ([]byte, error) {
+	v, ok := fk[bucket][key]
+	if !ok {
+		return nil, errors.New("nats: key not found")
+	}
+	return []byte(v), nil
+}
+
+func Test_natsKVSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `env:"app.host"`
+		Port int    `env:"app.port"`
+	}
+
+	kv := fakeNATSKV{
+		"config":  {"app.host": "example.com", "app.port": "8080", "other": "ignored"},
+		"invalid": {"app.port": "high"},
+	}
+
+	tests := []struct {
+		name    string
+		bucket  string
+		want    target
+		wantErr bool
+	}{
+		{"bucket", "config", target{"example.com", 8080}, false},
+		{"invalid value", "invalid", target{}, true},
+		{"missing bucket", "nope", target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&natsKVSource{kv: kv, bucket: tc.bucket}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
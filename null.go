@@ -0,0 +1,23 @@
+package primordius
+
+// NullSource is a Source which writes no values into the target. It counts how often
+// it was called and returns Err, which makes it useful for testing precedence, processing
+// order and error propagation without providing real data.
+type NullSource struct {
+	// Calls is the number of times ToTarget was called.
+	Calls int
+	// Err is returned by ToTarget.
+	Err error
+	// OnCall, if not nil, is called by ToTarget with the target, e.g. to record the
+	// order in which sources are processed.
+	OnCall func(t any)
+}
+
+// ToTarget records the call and returns ns.Err without touching t.
+func (ns *NullSource) ToTarget(t any) error {
+	ns.Calls++
+	if ns.OnCall != nil {
+		ns.OnCall(t)
+	}
+	return ns.Err
+}
@@ -0,0 +1,45 @@
+package primordius
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestNullSource(t *testing.T) {
+	var target struct {
+		Host string `json:"host"`
+	}
+	order := make([]string, 0)
+	first := &NullSource{OnCall: func(any) { order = append(order, "first") }}
+	second := &NullSource{OnCall: func(any) { order = append(order, "second") }}
+
+	pr := New(&target)
+	pr.AddSource(first)
+	pr.FromJSON([]byte(`{"host": "example.com"}`))
+	pr.AddSource(second)
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if first.Calls != 1 || second.Calls != 1 {
+		t.Errorf("Calls = %d, %d, want 1, 1", first.Calls, second.Calls)
+	}
+	if !reflect.DeepEqual(order, []string{"first", "second"}) {
+		t.Errorf("order = %v, want [first second]", order)
+	}
+	if target.Host != "example.com" {
+		t.Errorf("Host = %q, want %q", target.Host, "example.com")
+	}
+
+	errFailing := errors.New("failing")
+	last := &NullSource{}
+	pr.ResetSources()
+	pr.AddSource(&NullSource{Err: errFailing})
+	pr.AddSource(last)
+	if err := pr.Process(); !errors.Is(err, errFailing) {
+		t.Errorf("Process() error = %v, want %v", err, errFailing)
+	}
+	if last.Calls != 0 {
+		t.Errorf("source after failing source was called %d times", last.Calls)
+	}
+}
@@ -0,0 +1,139 @@
+package primordius
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// OCITitleAnnotation is the annotation naming the file a layer of an OCI artifact holds.
+const OCITitleAnnotation = "org.opencontainers.image.title"
+
+var ErrDigestMismatch = errors.New("content does not match digest")
+
+type (
+	// OCIRegistry is the subset of an OCI registry client required by the OCI source.
+	// Wrap your client of choice, e.g. of oras-go or go-containerregistry, to satisfy it.
+	OCIRegistry interface {
+		// Manifest returns the image manifest referenced by ref, e.g.
+		// "registry.example.com/ops/config@sha256:…" or "registry.example.com/ops/config:v1".
+		Manifest(ctx context.Context, ref string) ([]byte, error)
+		// Blob returns the content of the blob with the given digest in the repository of ref.
+		Blob(ctx context.Context, ref, digest string) ([]byte, error)
+	}
+	ociSource struct {
+		registry OCIRegistry
+		ref      string
+		file     string
+		format   Format
+	}
+	// ociDescriptor is a content descriptor of an OCI image manifest.
+	ociDescriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Annotations map[string]string `json:"annotations"`
+	}
+)
+
+func (ocs *ociSource) ToTarget(t any) error {
+	return decodeSource(ocs, t)
+}
+
+func (ocs *ociSource) describe() string {
+	return ocs.ref + " " + ocs.file
+}
+
+func (ocs *ociSource) load() ([]byte, Format, error) {
+	ctx := context.Background()
+	raw, err := ocs.registry.Manifest(ctx, ocs.ref)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching manifest of %s: %w", ocs.ref, err)
+	}
+	var manifest struct {
+		Layers []ociDescriptor `json:"layers"`
+	}
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, "", fmt.Errorf("decoding manifest of %s: %w", ocs.ref, err)
+	}
+	layer, err := ocs.layer(manifest.Layers)
+	if err != nil {
+		return nil, "", err
+	}
+	format, err := ocs.layerFormat(layer)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cont, err := ocs.registry.Blob(ctx, ocs.ref, layer.Digest)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching blob %s of %s: %w", layer.Digest, ocs.ref, err)
+	}
+	if err := verifyDigest(cont, layer.Digest); err != nil {
+		return nil, "", fmt.Errorf("blob of %s: %w", ocs.ref, err)
+	}
+
+	return cont, format, nil
+}
+
+// layer returns the layer holding the configured file, or the only layer if no
+// file is configured.
+func (ocs *ociSource) layer(layers []ociDescriptor) (ociDescriptor, error) {
+	if ocs.file == "" {
+		if len(layers) != 1 {
+			return ociDescriptor{}, fmt.Errorf("%s has %d layers, name the file to read", ocs.ref, len(layers))
+		}
+		return layers[0], nil
+	}
+	for _, l := range layers {
+		if l.Annotations[OCITitleAnnotation] == ocs.file {
+			return l, nil
+		}
+	}
+	return ociDescriptor{}, fmt.Errorf("%s has no layer holding %s", ocs.ref, ocs.file)
+}
+
+// layerFormat returns the configured Format or else infers it from the title of the
+// layer or its media type, e.g. "application/vnd.example.config.v1+yaml".
+func (ocs *ociSource) layerFormat(layer ociDescriptor) (Format, error) {
+	if ocs.format != "" {
+		return ocs.format, nil
+	}
+	if title := layer.Annotations[OCITitleAnnotation]; title != "" {
+		if format, err := formatFromExt(title); err == nil {
+			return format, nil
+		}
+	}
+	for _, format := range []Format{FormatJSON, FormatYAML, FormatTOML} {
+		if layer.MediaType == "application/"+string(format) || strings.HasSuffix(layer.MediaType, "+"+string(format)) {
+			return format, nil
+		}
+	}
+	return "", fmt.Errorf("%w: cannot infer format of layer %s of %s", ErrUnknownFormat, layer.Digest, ocs.ref)
+}
+
+// verifyDigest returns an error wrapping ErrDigestMismatch if content does not match
+// the sha256 digest. Digests of other algorithms are not verified.
+func verifyDigest(content []byte, digest string) error {
+	algorithm, hash, _ := strings.Cut(digest, ":")
+	if algorithm != "sha256" {
+		return nil
+	}
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != strings.ToLower(hash) {
+		return fmt.Errorf("%w %s", ErrDigestMismatch, digest)
+	}
+	return nil
+}
+
+// FromOCI adds a Source to pr which reads values from a layer of the OCI artifact
+// referenced by ref, e.g. pinned by digest, using registry. The layer is selected by
+// its title annotation matching file; if file is empty, the artifact must consist of
+// a single layer. If format is empty, it is inferred from the title or the media type
+// of the layer. The content of the layer is verified against its digest.
+func (pr *Primordius) FromOCI(registry OCIRegistry, ref, file string, format Format) {
+	pr.AddSource(&ociSource{registry: registry, ref: ref, file: file, format: format})
+}
@@ -0,0 +1,98 @@
+package primordius
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// fakeOCIRegistry serves manifests by reference and blobs by digest.
+type fakeOCIRegistry struct {
+	manifests map[string][]byte
+	blobs     map[string][]byte
+}
+
+func (fr *fakeOCIRegistry) Manifest(_ context.Context, ref string) ([]byte, error) {
+	m, ok := fr.manifests[ref]
+	if !ok {
+		return nil, errors.New("manifest unknown")
+	}
+	return m, nil
+}
+
+func (fr *fakeOCIRegistry) Blob(_ context.Context, _, digest string) ([]byte, error) {
+	b, ok := fr.blobs[digest]
+	if !ok {
+		return nil, errors.New("blob unknown")
+	}
+	return b, nil
+}
+
+// push stores a manifest under ref with a layer for each of the given descriptors,
+// whose digests are replaced by those of the respective contents.
+func (fr *fakeOCIRegistry) push(ref string, layers []ociDescriptor, contents []string) {
+	for i, cont := range contents {
+		sum := sha256.Sum256([]byte(cont))
+		if layers[i].Digest == "" {
+			layers[i].Digest = "sha256:" + hex.EncodeToString(sum[:])
+		}
+		fr.blobs[layers[i].Digest] = []byte(cont)
+	}
+	m, _ := json.Marshal(map[string]any{"schemaVersion": 2, "layers": layers})
+	fr.manifests[ref] = m
+}
+
+func TestPrimordius_FromOCI(t *testing.T) {
+	registry := &fakeOCIRegistry{manifests: make(map[string][]byte), blobs: make(map[string][]byte)}
+	registry.push("reg/single:v1", []ociDescriptor{{MediaType: "application/vnd.example.config.v1+yaml"}}, []string{"port: 80"})
+	registry.push("reg/multi:v1", []ociDescriptor{
+		{MediaType: "application/octet-stream", Annotations: map[string]string{OCITitleAnnotation: "app.json"}},
+		{MediaType: "application/octet-stream", Annotations: map[string]string{OCITitleAnnotation: "app.toml"}},
+	}, []string{`{"port": 81}`, "port = 82"})
+	registry.push("reg/tampered:v1", []ociDescriptor{
+		{MediaType: "application/octet-stream", Digest: "sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+	}, []string{`{"port": 83}`})
+
+	tests := []struct {
+		name    string
+		ref     string
+		file    string
+		format  Format
+		want    int
+		wantErr error
+	}{
+		{"single layer by media type", "reg/single:v1", "", "", 80, nil},
+		{"layer by title", "reg/multi:v1", "app.toml", "", 82, nil},
+		{"explicit format", "reg/multi:v1", "app.json", FormatYAML, 81, nil},
+		{"ambiguous layers", "reg/multi:v1", "", "", 0, errAny},
+		{"missing file", "reg/multi:v1", "app.yaml", "", 0, errAny},
+		{"unknown format", "reg/tampered:v1", "", "", 0, ErrUnknownFormat},
+		{"digest mismatch", "reg/tampered:v1", "", FormatJSON, 0, ErrDigestMismatch},
+		{"unknown reference", "reg/missing:v1", "", "", 0, errAny},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got struct {
+				Port int `json:"port" yaml:"port" toml:"port"`
+			}
+			pr := New(&got)
+			pr.FromOCI(registry, tc.ref, tc.file, tc.format)
+			err := pr.Process()
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("Process() error = %v", err)
+			case tc.wantErr == errAny && err == nil:
+				t.Fatal("Process() error = nil, want error")
+			case tc.wantErr != nil && tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+				t.Fatalf("Process() error = %v, want %v", err, tc.wantErr)
+			}
+			if got.Port != tc.want {
+				t.Errorf("Port = %d, want %d", got.Port, tc.want)
+			}
+		})
+	}
+}
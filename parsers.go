@@ -0,0 +1,46 @@
+package primordius
+
+import (
+	"reflect"
+	"sync"
+)
+
+// parseFunc converts a string value into a value of a registered type.
+type parseFunc func(val string) (reflect.Value, error)
+
+var (
+	parsersMu sync.RWMutex
+	parsers   = make(map[reflect.Type]parseFunc)
+)
+
+// RegisterParser registers fn to convert string values into fields of type T, replacing
+// any parser registered for T before, e.g. for exact decimals:
+//
+//	primordius.RegisterParser(func(val string) (decimal.Decimal, error) {
+//		return decimal.NewFromString(val)
+//	})
+//
+// Registered parsers take precedence over the built-in conversions as well as
+// UnmarshalEnv and UnmarshalText methods. They are used by all sources converting
+// string values, such as env, args, flags and defaults, for fields of type T, pointers
+// to T and slices of either.
+// Sources decoding files rely on the mechanisms of the respective decoder instead.
+// RegisterParser is safe for concurrent use.
+func RegisterParser[T any](fn func(val string) (T, error)) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+	parsers[t] = func(val string) (reflect.Value, error) {
+		v, err := fn(val)
+		return reflect.ValueOf(&v).Elem(), err
+	}
+}
+
+// parser returns the parser registered for t.
+func parser(t reflect.Type) (parseFunc, bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+	fn, ok := parsers[t]
+	return fn, ok
+}
@@ -0,0 +1,80 @@
+package primordius
+
+import (
+	"errors"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testDecimal is a fixed-point decimal with two fractional digits.
+type testDecimal struct {
+	cents int64
+}
+
+func parseTestDecimal(val string) (testDecimal, error) {
+	whole, frac, _ := strings.Cut(val, ".")
+	if len(frac) > 2 {
+		return testDecimal{}, errors.New("too many fractional digits")
+	}
+	cents, err := strconv.ParseInt(whole+(frac + "00")[:2], 10, 64)
+	return testDecimal{cents: cents}, err
+}
+
+func TestRegisterParser(t *testing.T) {
+	RegisterParser(parseTestDecimal)
+	RegisterParser(func(val string) (*big.Rat, error) {
+		r, ok := new(big.Rat).SetString(val)
+		if !ok {
+			return nil, errors.New("invalid rational number")
+		}
+		return r, nil
+	})
+	t.Cleanup(func() {
+		parsersMu.Lock()
+		defer parsersMu.Unlock()
+		delete(parsers, reflect.TypeOf(testDecimal{}))
+		delete(parsers, reflect.TypeOf((*big.Rat)(nil)))
+	})
+
+	type target struct {
+		Price    testDecimal   `env:"PRICE"`
+		Discount *testDecimal  `env:"DISCOUNT"`
+		Fees     []testDecimal `env:"FEES"`
+		Rate     *big.Rat      `env:"RATE"`
+		Missing  *testDecimal  `env:"MISSING"`
+		Ratios   []*big.Rat    `env:"RATIOS"`
+	}
+	tests := []struct {
+		name    string
+		args    []string
+		want    target
+		wantErr bool
+	}{
+		{
+			name: "registered types",
+			args: []string{"PRICE=19.99", "DISCOUNT=0.5", "FEES=1,2.25", "RATE=0.1", "RATIOS=1/3,2"},
+			want: target{Price: testDecimal{1999}, Discount: &testDecimal{50}, Fees: []testDecimal{{100}, {225}},
+				Rate: big.NewRat(1, 10), Ratios: []*big.Rat{big.NewRat(1, 3), big.NewRat(2, 1)}},
+		},
+		{name: "parser error", args: []string{"PRICE=1.999"}, wantErr: true},
+		{name: "parser error of pointer", args: []string{"RATE=x"}, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.FromArgs(tc.args)
+			err := pr.Process()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Process() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
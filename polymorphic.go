@@ -0,0 +1,297 @@
+package primordius
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v2"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const (
+	// DefaultDiscriminator is the key naming the concrete type of a polymorphic section.
+	DefaultDiscriminator = "type"
+	discriminatorTagName = "discriminator"
+)
+
+type (
+	// polymorphicField is a section of decoded data belonging to an interface field.
+	polymorphicField struct {
+		// index is the path of struct field indices from the target to the field.
+		index []int
+		// name is the dotted name of the field for error messages.
+		name string
+		// data is the decoded section, either a map or a slice of maps.
+		data any
+		// discriminator is the key naming the concrete type within the section.
+		discriminator string
+	}
+	// encodeFunc encodes v into the respective Format.
+	encodeFunc func(v any) ([]byte, error)
+)
+
+var (
+	typesMu sync.RWMutex
+	types   = make(map[reflect.Type]map[string]func() any)
+
+	// encoders holds the formats polymorphic sections can be decoded from.
+	encoders = map[Format]encodeFunc{
+		FormatJSON: json.Marshal,
+		FormatYAML: yaml.Marshal,
+		FormatTOML: func(v any) ([]byte, error) {
+			var buf bytes.Buffer
+			err := toml.NewEncoder(&buf).Encode(v)
+			return buf.Bytes(), err
+		},
+	}
+)
+
+// RegisterType registers factory to create the concrete type for fields of the
+// interface type I whose section names it by the discriminator value name, e.g.
+//
+//	primordius.RegisterType[Storage]("s3", func() Storage { return &S3Storage{} })
+//
+// for a section like {"type": "s3", "bucket": "data"}. The discriminator key
+// defaults to "type" and can be changed per field using the tag `discriminator:"kind"`.
+// Fields of type I as well as []I are supported. factory must return a pointer so the
+// section can be decoded into it. Polymorphic sections are supported in JSON, YAML and
+// TOML content. RegisterType is safe for concurrent use.
+func RegisterType[I any](name string, factory func() I) {
+	it := reflect.TypeOf((*I)(nil)).Elem()
+	if it.Kind() != reflect.Interface {
+		panic(fmt.Sprintf("primordius: RegisterType called with non-interface type %s", it))
+	}
+
+	typesMu.Lock()
+	defer typesMu.Unlock()
+	if types[it] == nil {
+		types[it] = make(map[string]func() any)
+	}
+	types[it][name] = func() any { return factory() }
+}
+
+// registeredInterface returns the interface type of fields of type t, which is
+// either a registered interface type or a slice of one.
+func registeredInterface(t reflect.Type) (reflect.Type, bool) {
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Interface {
+		return nil, false
+	}
+
+	typesMu.RLock()
+	defer typesMu.RUnlock()
+	_, ok := types[t]
+	return t, ok
+}
+
+// instantiate creates the concrete type registered for the interface type it
+// under the discriminator value of data.
+func instantiate(it reflect.Type, pf polymorphicField, data any) (reflect.Value, error) {
+	m, ok := data.(map[string]any)
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("field %s: section is not a map", pf.name)
+	}
+	name := fmt.Sprint(m[pf.discriminator])
+
+	typesMu.RLock()
+	factory, ok := types[it][name]
+	typesMu.RUnlock()
+	if !ok {
+		return reflect.Value{}, fmt.Errorf("field %s: no type registered for %s %q", pf.name, pf.discriminator, name)
+	}
+	v := reflect.ValueOf(factory())
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return reflect.Value{}, fmt.Errorf("field %s: type registered for %q is not a pointer", pf.name, name)
+	}
+
+	return v, nil
+}
+
+// hasPolymorphicFields reports whether the struct t contains fields of registered
+// interface types, descending into nested structs.
+func hasPolymorphicFields(t reflect.Type) bool {
+	typesMu.RLock()
+	empty := len(types) == 0
+	typesMu.RUnlock()
+
+	return !empty && containsPolymorphic(t, make(map[reflect.Type]bool))
+}
+
+func containsPolymorphic(t reflect.Type, visited map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		if _, ok := registeredInterface(sf.Type); ok || containsPolymorphic(sf.Type, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodePolymorphic decodes content into t, creating the concrete types of
+// interface fields as registered. The sections of such fields are removed from
+// the content, which is decoded into t as usual; then each section is decoded
+// into a newly created instance of the concrete type named by it.
+func decodePolymorphic(format Format, fn DecodeFunc, content []byte, t any) error {
+	encode, ok := encoders[format]
+	if !ok {
+		return fmt.Errorf("polymorphic sections are not supported in format %q", format)
+	}
+
+	raw, err := decodeMap(format, fn, content)
+	if err != nil {
+		return err
+	}
+	fields := extractPolymorphic(reflect.TypeOf(t).Elem(), raw, format, nil, "")
+
+	rest, err := encode(raw)
+	if err != nil {
+		return err
+	}
+	if err := fn(rest, t); err != nil {
+		return err
+	}
+
+	for _, pf := range fields {
+		f := fieldByIndex(reflect.ValueOf(t).Elem(), pf.index)
+		it, _ := registeredInterface(f.Type())
+		if f.Kind() != reflect.Slice {
+			v, err := decodeInstance(it, pf, pf.data, format, fn, encode)
+			if err != nil {
+				return err
+			}
+			f.Set(v)
+			continue
+		}
+
+		items, ok := pf.data.([]any)
+		if !ok {
+			return fmt.Errorf("field %s: section is not a list", pf.name)
+		}
+		sl := reflect.MakeSlice(f.Type(), len(items), len(items))
+		for i, item := range items {
+			v, err := decodeInstance(it, pf, item, format, fn, encode)
+			if err != nil {
+				return err
+			}
+			sl.Index(i).Set(v)
+		}
+		f.Set(sl)
+	}
+
+	return nil
+}
+
+// decodeInstance decodes data into a new instance of the concrete type it names.
+func decodeInstance(it reflect.Type, pf polymorphicField, data any, format Format, fn DecodeFunc, encode encodeFunc) (reflect.Value, error) {
+	v, err := instantiate(it, pf, data)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	cont, err := encode(data)
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	if err := fn(cont, v.Interface()); err != nil {
+		return reflect.Value{}, fmt.Errorf("field %s: %w", pf.name, err)
+	}
+
+	return v, nil
+}
+
+// extractPolymorphic removes the sections of all fields of registered interface
+// types of the struct t from raw and returns them.
+func extractPolymorphic(t reflect.Type, raw map[string]any, format Format, index []int, path string) []polymorphicField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	fields := make([]polymorphicField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		key, ok := rawKey(raw, sf, format)
+		if !ok {
+			continue
+		}
+		fieldIndex := append(append([]int{}, index...), i)
+		name := sf.Name
+		if path != "" {
+			name = path + "." + sf.Name
+		}
+
+		if _, ok := registeredInterface(sf.Type); ok {
+			if raw[key] == nil {
+				delete(raw, key)
+				continue
+			}
+			discriminator := sf.Tag.Get(discriminatorTagName)
+			if discriminator == "" {
+				discriminator = DefaultDiscriminator
+			}
+			fields = append(fields, polymorphicField{index: fieldIndex, name: name, data: raw[key], discriminator: discriminator})
+			delete(raw, key)
+			continue
+		}
+		if sub, ok := raw[key].(map[string]any); ok {
+			fields = append(fields, extractPolymorphic(sf.Type, sub, format, fieldIndex, name)...)
+		}
+	}
+
+	return fields
+}
+
+// rawKey returns the key of raw holding the value of the field sf, using the name
+// from the tag of format or the field name, matched case-insensitively.
+func rawKey(raw map[string]any, sf reflect.StructField, format Format) (string, bool) {
+	name, _, _ := strings.Cut(sf.Tag.Get(string(format)), ",")
+	if name == "-" {
+		return "", false
+	}
+	if name == "" {
+		name = sf.Name
+	}
+	if _, ok := raw[name]; ok {
+		return name, true
+	}
+	for key := range raw {
+		if strings.EqualFold(key, name) {
+			return key, true
+		}
+	}
+	return "", false
+}
+
+// fieldByIndex returns the nested field of the struct v at index, allocating nil
+// pointers to structs on the way.
+func fieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for _, i := range index {
+		for v.Kind() == reflect.Pointer {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(i)
+	}
+	return v
+}
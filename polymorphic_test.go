@@ -0,0 +1,98 @@
+package primordius
+
+import (
+	"reflect"
+	"testing"
+)
+
+type (
+	testStorage interface {
+		Kind() string
+	}
+	testS3Storage struct {
+		Bucket string `json:"bucket" yaml:"bucket" toml:"bucket"`
+	}
+	testDiskStorage struct {
+		Path string `json:"path" yaml:"path" toml:"path"`
+	}
+)
+
+func (s *testS3Storage) Kind() string   { return "s3" }
+func (d *testDiskStorage) Kind() string { return "disk" }
+
+func TestRegisterType(t *testing.T) {
+	RegisterType[testStorage]("s3", func() testStorage { return &testS3Storage{} })
+	RegisterType[testStorage]("disk", func() testStorage { return &testDiskStorage{} })
+	t.Cleanup(func() {
+		typesMu.Lock()
+		defer typesMu.Unlock()
+		delete(types, reflect.TypeOf((*testStorage)(nil)).Elem())
+	})
+
+	type backup struct {
+		Targets []testStorage `json:"targets" yaml:"targets" toml:"targets" discriminator:"kind"`
+	}
+	type target struct {
+		Name    string      `json:"name" yaml:"name" toml:"name"`
+		Storage testStorage `json:"storage" yaml:"storage" toml:"storage"`
+		Backup  backup      `json:"backup" yaml:"backup" toml:"backup"`
+	}
+	want := target{
+		Name:    "app",
+		Storage: &testS3Storage{Bucket: "data"},
+		Backup:  backup{Targets: []testStorage{&testDiskStorage{Path: "/mnt"}, &testS3Storage{Bucket: "archive"}}},
+	}
+
+	tests := []struct {
+		name    string
+		format  Format
+		content string
+		wantErr bool
+	}{
+		{"JSON", FormatJSON, `{"name": "app", "storage": {"type": "s3", "bucket": "data"},
+			"backup": {"targets": [{"kind": "disk", "path": "/mnt"}, {"kind": "s3", "bucket": "archive"}]}}`, false},
+		{"YAML", FormatYAML, "name: app\nstorage:\n  type: s3\n  bucket: data\nbackup:\n  targets:\n" +
+			"    - kind: disk\n      path: /mnt\n    - kind: s3\n      bucket: archive", false},
+		{"TOML", FormatTOML, "name = \"app\"\n[storage]\ntype = \"s3\"\nbucket = \"data\"\n" +
+			"[[backup.targets]]\nkind = \"disk\"\npath = \"/mnt\"\n[[backup.targets]]\nkind = \"s3\"\nbucket = \"archive\"", false},
+		{"unknown type", FormatJSON, `{"storage": {"type": "ftp"}}`, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := decode(tc.format, []byte(tc.content), &got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("decode() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, want) {
+				t.Errorf("decode() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestRegisterType_numbers(t *testing.T) {
+	RegisterType[testStorage]("s3", func() testStorage { return &testS3Storage{} })
+	t.Cleanup(func() {
+		typesMu.Lock()
+		defer typesMu.Unlock()
+		delete(types, reflect.TypeOf((*testStorage)(nil)).Elem())
+	})
+
+	type target struct {
+		Storage testStorage `json:"storage"`
+		Int     int64       `json:"int"`
+		Uint    uint64      `json:"uint"`
+	}
+	want := target{Storage: &testS3Storage{Bucket: "data"}, Int: 9007199254740993, Uint: 18446744073709551615}
+
+	var got target
+	content := `{"storage": {"type": "s3", "bucket": "data"}, "int": 9007199254740993, "uint": 18446744073709551615}`
+	if err := decode(FormatJSON, []byte(content), &got); err != nil {
+		t.Fatalf("decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decode() got = %+v, want %+v", got, want)
+	}
+}
@@ -1,19 +1,29 @@
 package primordius
 
 import (
-	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/BurntSushi/toml"
-	"gopkg.in/yaml.v2"
 	"io"
 	"os"
 	"reflect"
-	"strconv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 )
 
 const tagName = "env"
 
-var ErrInvalidSpecification = errors.New("specification must be a struct pointer")
+var (
+	ErrInvalidSpecification = errors.New("specification must be a struct pointer")
+	ErrNotProcessed         = errors.New("sources have not been processed yet")
+	ErrUnknownKeys          = errors.New("content contains unknown keys")
+
+	// ErrKeyNotFound is returned by a RedisClient for a missing key and wrapped by
+	// the errors of sources reading a file section under a key path that doesn't exist.
+	ErrKeyNotFound = errors.New("key not found")
+)
 
 type (
 	// Source defines an origin writing found configuration values into t.
@@ -23,8 +33,32 @@ type (
 	}
 	// Primordius manages sources and processes them into the set target.
 	Primordius struct {
-		target  any
-		sources []Source
+		// mu guards sources, the target and the state of the last run of Process.
+		mu         sync.RWMutex
+		target     any
+		sources    []Source
+		captureRaw bool
+		rawData    map[int]map[string]any
+		strictTOML bool
+		// collectErrors makes Process continue after a source failed.
+		collectErrors bool
+		// coerceScalars reconciles mismatching scalar types of decoded content.
+		coerceScalars bool
+		// concurrentLoading makes Process load the content of all sources up front.
+		concurrentLoading bool
+		loaded            map[int]loadResult
+
+		deepMergeMaps bool
+		mapMerge      *mapMerge
+
+		conv           *converter
+		secretResolver SecretResolver
+		validator      func(target any) error
+		trace          *trace
+		fingerprint    snapshot
+
+		watchInterval time.Duration
+		watchDebounce time.Duration
 	}
 	yamlFileSource struct {
 		name string
@@ -53,176 +87,479 @@ type (
 	tomlReaderSource struct {
 		r io.Reader
 	}
+	envValue struct {
+		field string
+		val   string
+	}
+	// EnvOptions configures a source added using FromEnvWithOptions.
+	EnvOptions struct {
+		// CaseInsensitive makes the source fall back to a variable whose name differs from
+		// the expected one in case only, e.g. "my_app_port" for "MY_APP_PORT", if no
+		// variable of the exact name is set.
+		CaseInsensitive bool
+		// Delimiter separates the elements of slice values and the pairs of map values
+		// instead of DefaultDelimiter, unless the delimiter option of a field's tag is set.
+		Delimiter string
+		// TrimSpace removes leading and trailing white space from all values.
+		TrimSpace bool
+		// ErrorOnMissing makes the source fail with an error wrapping ErrMissingRequired
+		// if the variable of a field marked as required isn't set, even if another
+		// source provides the value.
+		ErrorOnMissing bool
+	}
 	envSource struct {
-		prefix string
+		prefix  string
+		opts    EnvOptions
+		conv    *converter
+		lookups []EnvLookup
+		// values maps the names of the variables found during the last run to their
+		// values and the fields they were applied to.
+		values map[string]envValue
+		// changed holds the paths of the fields whose values changed in the last run.
+		changed []string
+	}
+	conditionalSource struct {
+		cond   func() bool
+		source Source
 	}
 )
 
 func (y *yamlFileSource) ToTarget(t any) error {
-	cont, err := os.ReadFile(y.name)
-	if err != nil {
-		return err
-	}
+	return decodeSource(y, t)
+}
 
-	return yaml.Unmarshal(cont, t)
+func (y *yamlFileSource) describe() string {
+	return y.name
+}
+
+func (y *yamlFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(y.name)
+	return cont, FormatYAML, err
 }
 
 func (y *yamlContentSource) ToTarget(t any) error {
-	return yaml.Unmarshal(y.content, t)
+	return decodeSource(y, t)
+}
+
+func (y *yamlContentSource) load() ([]byte, Format, error) {
+	return y.content, FormatYAML, nil
 }
 
 func (y *yamlReaderSource) ToTarget(t any) error {
-	cont, err := io.ReadAll(y.r)
-	if err != nil {
-		return err
-	}
+	return decodeSource(y, t)
+}
 
-	return yaml.Unmarshal(cont, t)
+func (y *yamlReaderSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(y.r)
+	return cont, FormatYAML, err
 }
 
 func (j *jsonFileSource) ToTarget(t any) error {
-	cont, err := os.ReadFile(j.name)
-	if err != nil {
-		return err
-	}
+	return decodeSource(j, t)
+}
 
-	return json.Unmarshal(cont, t)
+func (j *jsonFileSource) describe() string {
+	return j.name
+}
+
+func (j *jsonFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(j.name)
+	return cont, FormatJSON, err
 }
 
 func (j *jsonContentSource) ToTarget(t any) error {
-	return json.Unmarshal(j.content, t)
+	return decodeSource(j, t)
 }
 
-func (y *jsonReaderSource) ToTarget(t any) error {
-	cont, err := io.ReadAll(y.r)
-	if err != nil {
-		return err
-	}
+func (j *jsonContentSource) load() ([]byte, Format, error) {
+	return j.content, FormatJSON, nil
+}
 
-	return json.Unmarshal(cont, t)
+func (j *jsonReaderSource) ToTarget(t any) error {
+	return decodeSource(j, t)
+}
+
+func (j *jsonReaderSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(j.r)
+	return cont, FormatJSON, err
 }
 
 func (to *tomlFileSource) ToTarget(t any) error {
-	_, err := toml.DecodeFile(to.name, t)
-	return err
+	return decodeSource(to, t)
+}
+
+func (to *tomlFileSource) describe() string {
+	return to.name
+}
+
+func (to *tomlFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(to.name)
+	return cont, FormatTOML, err
 }
 
 func (to *tomlContentSource) ToTarget(t any) error {
-	_, err := toml.Decode(string(to.content), t)
-	return err
+	return decodeSource(to, t)
+}
+
+func (to *tomlContentSource) load() ([]byte, Format, error) {
+	return to.content, FormatTOML, nil
 }
 
 func (to *tomlReaderSource) ToTarget(t any) error {
+	return decodeSource(to, t)
+}
+
+func (to *tomlReaderSource) load() ([]byte, Format, error) {
 	cont, err := io.ReadAll(to.r)
-	if err != nil {
-		return err
-	}
+	return cont, FormatTOML, err
+}
+
+func (es *envSource) ToTarget(spec any) error {
+	es.lookups = es.lookups[:0]
+	previous := es.values
+	es.values = make(map[string]envValue)
+	err := es.conv.applyTagged(spec, es)
+	es.changed = es.diff(previous)
 
-	_, err = toml.Decode(string(cont), t)
 	return err
 }
 
-func (es *envSource) ToTarget(spec any) error {
-	valueOf := reflect.ValueOf(spec)
+func (es *envSource) describe() string {
+	return es.fullPrefix()
+}
 
-	if valueOf.Kind() != reflect.Pointer {
-		return ErrInvalidSpecification
+func (es *envSource) lookup(path, key string) (string, bool) {
+	name := es.fullPrefix() + key
+	if !es.conv.envAllowed(name) {
+		return "", false
+	}
+	val, exists := os.LookupEnv(name)
+	if !exists && es.opts.CaseInsensitive {
+		name, val, exists = es.lookupFold(name)
 	}
-	s := valueOf.Elem()
-	if s.Kind() != reflect.Struct {
-		return ErrInvalidSpecification
+	if es.opts.TrimSpace {
+		val = strings.TrimSpace(val)
 	}
+	es.lookups = append(es.lookups, EnvLookup{Field: path, Key: name, Found: exists})
+	if exists {
+		es.values[name] = envValue{field: path, val: val}
+	}
+	return val, exists
+}
 
-	t := s.Type()
-	for i := 0; i < s.NumField(); i++ {
-		f := s.Field(i)
+func (es *envSource) defaultOptions() tagOptions {
+	if es.opts.Delimiter == "" {
+		return nil
+	}
+	return tagOptions{"delimiter": es.opts.Delimiter}
+}
 
-		if !f.IsValid() {
-			continue
+func (es *envSource) requireValues() bool {
+	return es.opts.ErrorOnMissing
+}
+
+// lookupFold returns the name and value of an allowed variable whose name equals name
+// except for case. It returns name unchanged if there is none.
+func (es *envSource) lookupFold(name string) (string, string, bool) {
+	for _, env := range os.Environ() {
+		n, val, _ := strings.Cut(env, "=")
+		if strings.EqualFold(n, name) && es.conv.envAllowed(n) {
+			return n, val, true
 		}
-		tagVal := t.Field(i).Tag.Get(tagName)
-		if tagVal == "" || tagVal == "-" {
-			continue
+	}
+	return name, "", false
+}
+
+// diff returns the sorted paths of the fields whose variables were set, unset or
+// changed their values compared to the previous values.
+func (es *envSource) diff(previous map[string]envValue) []string {
+	seen := make(map[string]bool)
+	fields := make([]string, 0)
+	add := func(field string) {
+		if !seen[field] {
+			seen[field] = true
+			fields = append(fields, field)
 		}
-		val, exists := os.LookupEnv(es.prefix + tagVal)
-		if !exists {
-			continue
+	}
+	for key, cur := range es.values {
+		if old, ok := previous[key]; !ok || old.val != cur.val {
+			add(cur.field)
 		}
+	}
+	for key, old := range previous {
+		if _, ok := es.values[key]; !ok {
+			add(old.field)
+		}
+	}
+	sort.Strings(fields)
 
-		switch f.Kind() {
-		case reflect.String:
-			f.SetString(val)
-		case reflect.Int:
-			fallthrough
-		case reflect.Int8:
-			fallthrough
-		case reflect.Int16:
-			fallthrough
-		case reflect.Int32:
-			fallthrough
-		case reflect.Int64:
-			v, err := strconv.ParseInt(val, 10, 64)
-			if err != nil {
-				return err
-			}
-			f.SetInt(v)
-		case reflect.Uint:
-			fallthrough
-		case reflect.Uint8:
-			fallthrough
-		case reflect.Uint16:
-			fallthrough
-		case reflect.Uint32:
-			fallthrough
-		case reflect.Uint64:
-			v, err := strconv.ParseUint(val, 10, 64)
-			if err != nil {
-				return err
-			}
-			f.SetUint(v)
-		case reflect.Bool:
-			v, err := strconv.ParseBool(val)
-			if err != nil {
-				return err
-			}
-			f.SetBool(v)
-		case reflect.Float32:
-			fallthrough
-		case reflect.Float64:
-			v, err := strconv.ParseFloat(val, 64)
-			if err != nil {
-				return err
-			}
-			f.SetFloat(v)
-		case reflect.Slice:
-			f.SetBytes([]byte(val))
+	return fields
+}
+
+// fullPrefix returns the prefix followed by the separator, unless the prefix is empty
+// or already ends with the separator.
+func (es *envSource) fullPrefix() string {
+	return es.conv.envPrefix(es.prefix)
+}
+
+// keys returns the names of all environment variables starting with the prefix,
+// with the prefix removed.
+func (es *envSource) keys() []string {
+	prefix := es.fullPrefix()
+	keys := make([]string, 0)
+	for _, env := range os.Environ() {
+		name, _, _ := strings.Cut(env, "=")
+		if !es.conv.envAllowed(name) {
+			continue
+		}
+		if key := strings.TrimPrefix(name, prefix); key != name || prefix == "" {
+			keys = append(keys, key)
 		}
+	}
+	return keys
+}
 
+func (cs *conditionalSource) ToTarget(t any) error {
+	if !cs.cond() {
+		return nil
 	}
 
-	return nil
+	return cs.source.ToTarget(t)
+}
+
+func (cs *conditionalSource) describe() string {
+	return sourceDescription(cs.source)
+}
+
+// When returns a Source which only writes values into the target by means of s
+// if cond returns true at processing time. Otherwise, it does nothing.
+func When(cond func() bool, s Source) Source {
+	return &conditionalSource{cond: cond, source: s}
 }
 
 // New allocates and returns a new instance of Primordius with the supplied target.
-// target MUST be a pointer to a struct.
+// target MUST be a pointer to a struct; otherwise, Process fails with an error
+// wrapping ErrInvalidSpecification before any source is called.
 func New(target any) *Primordius {
 	return &Primordius{
 		target: target,
+		conv:   &converter{envSeparator: DefaultEnvSeparator},
 	}
 }
 
 // Process calls all registered Sources to write values into pr.target.
 // Registered sources are processed in the order they were initially added.
+// Afterwards, secret references are resolved and the configuration is validated
+// (see SetValidator).
+//
+// Process, ProcessFields, Reload and AddSource are safe for concurrent use; to read
+// the target while it may be processed concurrently, use View or the getters like
+// GetString.
 func (pr *Primordius) Process() error {
-	for _, s := range pr.sources {
-		if err := s.ToTarget(pr.target); err != nil {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	return pr.processTarget()
+}
+
+// processTarget processes all sources into pr.target and records its fingerprint.
+func (pr *Primordius) processTarget() error {
+	if err := pr.process(pr.target); err != nil {
+		return err
+	}
+	fingerprint, err := takeSnapshot(pr.target)
+	if err != nil {
+		return err
+	}
+	pr.fingerprint = fingerprint
+
+	return nil
+}
+
+// View calls fn while no processing takes place, so fn can safely read the target
+// even if Process, ProcessFields or Reload are called concurrently. fn must not call
+// methods of pr.
+func (pr *Primordius) View(fn func()) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	fn()
+}
+
+// checkTarget returns an error wrapping ErrInvalidSpecification and naming the actual
+// type of target if it is not a non-nil pointer to a struct.
+func checkTarget(target any) error {
+	v := reflect.ValueOf(target)
+	switch {
+	case !v.IsValid():
+		return fmt.Errorf("%w, got nil", ErrInvalidSpecification)
+	case v.Kind() != reflect.Pointer:
+		return fmt.Errorf("%w, got %s", ErrInvalidSpecification, v.Kind())
+	case v.IsNil():
+		return fmt.Errorf("%w, got nil pointer", ErrInvalidSpecification)
+	case v.Elem().Kind() != reflect.Struct:
+		return fmt.Errorf("%w, got pointer to %s", ErrInvalidSpecification, v.Elem().Kind())
+	}
+	return nil
+}
+
+// process runs all registered sources as well as the post-processing steps on target.
+func (pr *Primordius) process(target any) error {
+	if err := checkTarget(target); err != nil {
+		return err
+	}
+	pr.rawData = make(map[int]map[string]any)
+	pr.trace = newTrace()
+	pr.conv.warnings = nil
+	pr.mapMerge = nil
+	if pr.deepMergeMaps {
+		pr.mapMerge = &mapMerge{origins: make(map[string]int)}
+	}
+	if pr.concurrentLoading {
+		pr.loaded = pr.preload()
+		defer func() { pr.loaded = nil }()
+	}
+	before, err := takeSnapshot(target)
+	if err != nil {
+		return err
+	}
+	errs := make([]error, 0)
+	for i, s := range pr.sources {
+		if err := pr.processSource(target, i, s); err != nil {
+			err = fmt.Errorf("source #%d %s: %w", i, sourceDescription(s), err)
+			if !pr.collectErrors {
+				return err
+			}
+			errs = append(errs, err)
+		}
+		after, err := takeSnapshot(target)
+		if err != nil {
+			return err
+		}
+		pr.trace.record(i, s, before, after)
+		before = after
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	if err := pr.resolveSecrets(target); err != nil {
+		return err
+	}
+
+	return pr.validate(target)
+}
+
+// processSource applies s, registered at index i, to target, followed by the steps
+// required after each source.
+func (pr *Primordius) processSource(target any, i int, s Source) error {
+	var maps map[string]map[string]any
+	if pr.mapMerge != nil {
+		maps = takeMaps(target)
+	}
+	if err := pr.apply(target, i, s); err != nil {
+		return err
+	}
+	if pr.mapMerge != nil {
+		if err := pr.mapMerge.merge(target, i, maps); err != nil {
+			return err
+		}
+	}
+	if err := pr.conv.resetUnset(target); err != nil {
+		return err
+	}
+
+	return coerceCases(reflect.ValueOf(target), "", make(map[visit]bool))
+}
+
+// apply writes the values of s, registered at index i, into target.
+func (pr *Primordius) apply(target any, i int, s Source) error {
+	ls, ok := s.(loadingSource)
+	if !ok {
+		return s.ToTarget(target)
+	}
+
+	cont, format, err := pr.load(i, ls)
+	if err != nil || cont == nil {
+		return err
+	}
+	content := cont
+	if pr.coerceScalars {
+		if content, err = coerceContent(format, cont, reflect.TypeOf(target)); err != nil {
 			return err
 		}
 	}
+	if err := pr.decode(format, content, target); err != nil {
+		return &decodeError{err: err}
+	}
+	if !pr.captureRaw {
+		return nil
+	}
+	raw := make(map[string]any)
+	if err := decode(format, cont, &raw); err != nil {
+		return err
+	}
+	pr.rawData[i] = normalizeMap(raw)
+
+	return nil
+}
+
+// decode unmarshals content into t according to format, honoring the settings of pr.
+func (pr *Primordius) decode(format Format, content []byte, t any) error {
+	if format != FormatTOML || !pr.strictTOML {
+		return decode(format, content, t)
+	}
+
+	md, err := toml.Decode(string(stripBOM(content)), t)
+	if err != nil {
+		return err
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		keys := make([]string, 0, len(undecoded))
+		for _, key := range undecoded {
+			keys = append(keys, key.String())
+		}
+		return fmt.Errorf("%w: %s", ErrUnknownKeys, strings.Join(keys, ", "))
+	}
 
 	return nil
 }
 
+// SetStrictTOML controls whether TOML content containing keys which are not present
+// in the target is rejected with an error wrapping ErrUnknownKeys. This catches typos
+// in TOML files.
+func (pr *Primordius) SetStrictTOML(strict bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.strictTOML = strict
+}
+
+// SetCollectErrors controls whether Process continues with the remaining sources if
+// a source fails. If enabled, Process returns the errors of all failed sources combined
+// using errors.Join, each prefixed with the index and type of the source, and skips
+// resolving secrets and validation. By default, Process returns the first error.
+func (pr *Primordius) SetCollectErrors(collect bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.collectErrors = collect
+}
+
+// SetCaptureRawData controls whether sources decoding raw content (files, blocks,
+// readers etc.) additionally store the decoded data as a generic map during Process.
+// Captured data can be retrieved using RawData.
+func (pr *Primordius) SetCaptureRawData(capture bool) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.captureRaw = capture
+}
+
+// RawData returns the generic data decoded by the source registered at sourceIndex
+// during the last call to Process. It returns nil if capturing is disabled or the
+// source at sourceIndex does not decode raw content.
+func (pr *Primordius) RawData(sourceIndex int) map[string]any {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	return pr.rawData[sourceIndex]
+}
+
 // FromYAMLFile adds a Source to pr which reads values from a YAML file.
 func (pr *Primordius) FromYAMLFile(name string) {
 	pr.AddSource(&yamlFileSource{name: name})
@@ -265,18 +602,70 @@ func (pr *Primordius) FromTOMLReader(r io.Reader) {
 	pr.AddSource(&tomlReaderSource{r: r})
 }
 
-// FromEnv adds a Source to pr which reads values from environment variables.
+// FromEnv adds a Source to pr which reads values from environment variables. The name
+// of a variable consists of the prefix, a separator and the key from the env tag, e.g.
+// "MY_APP_PORT" for the prefix "MY_APP" and the key "PORT". The separator, "_" by default,
+// is only inserted if the prefix is not empty and doesn't end with it already, so the
+// prefix "MY_APP_" works as well. See SetEnvSeparator.
 func (pr *Primordius) FromEnv(prefix string) {
-	pr.AddSource(&envSource{prefix: prefix})
+	pr.AddSource(&envSource{prefix: prefix, conv: pr.conv})
+}
+
+// FromEnvWithOptions adds a Source to pr which reads values from environment variables
+// like FromEnv, configured by opts.
+func (pr *Primordius) FromEnvWithOptions(prefix string, opts EnvOptions) {
+	pr.AddSource(&envSource{prefix: prefix, opts: opts, conv: pr.conv})
 }
 
 // AddSource adds a Source s to pr to obtain arbitrary configuration values from.
 // Can also be used to add a custom Source.
 func (pr *Primordius) AddSource(s Source) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 	pr.sources = append(pr.sources, s)
 }
 
+// InsertSource inserts the Source s into the processing order of pr at index, so that
+// it is processed before the source previously registered at index. This allows e.g.
+// adding a source of defaults after other sources were added. A negative index inserts
+// s at the front, an index beyond the number of sources at the end.
+func (pr *Primordius) InsertSource(index int, s Source) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if index < 0 {
+		index = 0
+	}
+	if index > len(pr.sources) {
+		index = len(pr.sources)
+	}
+	pr.sources = append(pr.sources, nil)
+	copy(pr.sources[index+1:], pr.sources[index:])
+	pr.sources[index] = s
+}
+
 // ResetSources empties the internal list of registered Sources.
 func (pr *Primordius) ResetSources() {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
 	pr.sources = make([]Source, 0, 5)
 }
+
+// Reset empties the internal list of registered Sources like ResetSources and sets
+// all fields of the target to their zero values, so the target can be processed from
+// scratch. Information about the last call to Process, such as the precedence report,
+// is discarded as well. It returns an error wrapping ErrInvalidSpecification if the
+// target isn't a pointer to a struct.
+func (pr *Primordius) Reset() error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if err := checkTarget(pr.target); err != nil {
+		return err
+	}
+
+	v := reflect.ValueOf(pr.target).Elem()
+	v.Set(reflect.Zero(v.Type()))
+	pr.sources = make([]Source, 0, 5)
+	pr.trace, pr.fingerprint, pr.rawData = nil, nil, nil
+
+	return nil
+}
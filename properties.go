@@ -0,0 +1,226 @@
+package primordius
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatProperties  Format = "properties"
+	propertiesTagName        = "properties"
+)
+
+type (
+	propertiesFileSource struct {
+		name string
+	}
+	propertiesContentSource struct {
+		content []byte
+	}
+	propertiesReaderSource struct {
+		r io.Reader
+	}
+)
+
+func init() {
+	RegisterCodec(FormatProperties, decodeProperties)
+}
+
+func (ps *propertiesFileSource) ToTarget(t any) error {
+	return decodeSource(ps, t)
+}
+
+func (ps *propertiesFileSource) describe() string {
+	return ps.name
+}
+
+func (ps *propertiesFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(ps.name)
+	return cont, FormatProperties, err
+}
+
+func (ps *propertiesFileSource) modTime() time.Time { return fileModTime(ps.name) }
+
+func (ps *propertiesContentSource) ToTarget(t any) error {
+	return decodeSource(ps, t)
+}
+
+func (ps *propertiesContentSource) load() ([]byte, Format, error) {
+	return ps.content, FormatProperties, nil
+}
+
+func (ps *propertiesReaderSource) ToTarget(t any) error {
+	return decodeSource(ps, t)
+}
+
+func (ps *propertiesReaderSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(ps.r)
+	return cont, FormatProperties, err
+}
+
+// decodeProperties decodes Java properties content into t, which points to a struct
+// or a map[string]any. Dotted keys such as database.replica.host address nested
+// structs, just like the sections of INI content. Fields are matched by their
+// properties tag, else the key of their env tag, else their name, case-insensitively.
+// Values are converted like those of environment variables.
+func decodeProperties(content []byte, t any) error {
+	props, err := parseProperties(string(content))
+	if err != nil {
+		return err
+	}
+
+	sections := iniSections{"": make(map[string]string)}
+	for key, val := range props {
+		section := ""
+		if dot := strings.LastIndex(key, "."); dot >= 0 {
+			section, key = key[:dot], key[dot+1:]
+		}
+		if sections[section] == nil {
+			sections[section] = make(map[string]string)
+		}
+		sections[section][key] = val
+	}
+
+	return sections.decode(t, propertiesTagName)
+}
+
+// parseProperties parses Java properties content. Lines starting with # or ! are
+// comments. Keys and values are separated by =, : or whitespace, and a line ending
+// with a backslash continues on the next one, ignoring its leading whitespace. Keys
+// and values may contain the escape sequences \t, \n, \r, \f and \uXXXX; other
+// escaped characters, e.g. "\=" in keys, stand for themselves.
+func parseProperties(content string) (map[string]string, error) {
+	props := make(map[string]string)
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := trimPropertiesLine(lines[i])
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		for continues(line) {
+			line = line[:len(line)-1]
+			if i+1 == len(lines) {
+				break
+			}
+			i++
+			line += trimPropertiesLine(lines[i])
+		}
+
+		rawKey, rawVal := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", start, err)
+		}
+		val, err := unescapeProperty(rawVal)
+		if err != nil {
+			return nil, fmt.Errorf("properties: line %d: %w", start, err)
+		}
+		props[key] = val
+	}
+
+	return props, nil
+}
+
+// trimPropertiesLine removes leading whitespace and a trailing carriage return from line.
+func trimPropertiesLine(line string) string {
+	return strings.TrimLeft(strings.TrimSuffix(line, "\r"), " \t\f")
+}
+
+// continues reports whether line ends with an odd number of backslashes, i.e. an
+// unescaped one.
+func continues(line string) bool {
+	n := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		n++
+	}
+	return n%2 == 1
+}
+
+// splitProperty splits line at the first unescaped separator into the raw key and value.
+func splitProperty(line string) (string, string) {
+	i := 0
+	for ; i < len(line); i++ {
+		if line[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.IndexByte("=: \t\f", line[i]) >= 0 {
+			break
+		}
+	}
+	if i >= len(line) {
+		return line, ""
+	}
+
+	key, rest := line[:i], strings.TrimLeft(line[i:], " \t\f")
+	if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+		rest = strings.TrimLeft(rest[1:], " \t\f")
+	}
+	return key, rest
+}
+
+// unescapeProperty replaces the escape sequences in s.
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 == len(s) {
+			break
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf("incomplete unicode escape %q", s[i-1:])
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf("invalid unicode escape %q", s[i-1:i+5])
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+
+	return b.String(), nil
+}
+
+// FromPropertiesFile adds a Source to pr which reads values from a Java properties
+// file. Dotted keys such as database.host are assigned to the fields of nested structs.
+// Fields are matched by their properties tag, else the key of their env tag, else
+// their name, case-insensitively.
+func (pr *Primordius) FromPropertiesFile(name string) {
+	pr.AddSource(&propertiesFileSource{name: name})
+}
+
+// FromProperties adds a Source to pr which reads values from a properties block.
+func (pr *Primordius) FromProperties(content []byte) {
+	pr.AddSource(&propertiesContentSource{content: content})
+}
+
+// FromPropertiesReader adds a Source to pr which reads properties content from r.
+func (pr *Primordius) FromPropertiesReader(r io.Reader) {
+	pr.AddSource(&propertiesReaderSource{r: r})
+}
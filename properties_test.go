@@ -0,0 +1,115 @@
+package primordius
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_parseProperties(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:    "separators",
+			content: "a=1\nb: 2\r\nc 3\n  d = 4 \ne\n",
+			want:    map[string]string{"a": "1", "b": "2", "c": "3", "d": "4 ", "e": ""},
+		},
+		{
+			name:    "comments",
+			content: "# comment\n! another = comment\n  #indented\nkey = value # not a comment",
+			want:    map[string]string{"key": "value # not a comment"},
+		},
+		{
+			name:    "continuations",
+			content: "list = a, \\\n       b, \\\n\tc\nnext = x\\\\\nlast = y\\",
+			want:    map[string]string{"list": "a, b, c", "next": `x\`, "last": "y"},
+		},
+		{
+			name:    "escapes",
+			content: `key\=with\:separators\ and\ spaces = tab\there\nnewä`,
+			want:    map[string]string{"key=with:separators and spaces": "tab\there\nnewä"},
+		},
+		{name: "invalid unicode escape", content: `key = \uzzzz`, wantErr: true},
+		{name: "incomplete unicode escape", content: `key = \u00`, wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := parseProperties(tc.content)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("parseProperties() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("parseProperties() got = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_propertiesSources(t *testing.T) {
+	type replica struct {
+		Host string `properties:"host"`
+	}
+	type database struct {
+		Host    string        `properties:"host"`
+		Port    int           `env:"PORT"`
+		Timeout time.Duration `properties:"timeout"`
+		Replica *replica      `properties:"replica"`
+	}
+	type target struct {
+		Name     string   `properties:"name"`
+		Debug    bool     `properties:"-"`
+		Tags     []string `properties:"tags"`
+		Database database `properties:"database"`
+		Cache    *database
+	}
+	content := `# application
+Name = app
+debug = true
+tags = a,\
+       b
+database.host = db.local
+database.port: 5432
+database.timeout 5s
+database.replica.host = replica.local
+`
+	want := target{Name: "app", Tags: []string{"a", "b"},
+		Database: database{Host: "db.local", Port: 5432, Timeout: 5 * time.Second, Replica: &replica{Host: "replica.local"}}}
+	name := filepath.Join(t.TempDir(), "config.properties")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{"file", &propertiesFileSource{name: name}, false},
+		{"content", &propertiesContentSource{content: []byte(content)}, false},
+		{"reader", &propertiesReaderSource{r: strings.NewReader(content)}, false},
+		{"generic file", &fileSource{name: name}, false},
+		{"missing file", &propertiesFileSource{name: name + ".missing"}, true},
+		{"invalid value", &propertiesContentSource{content: []byte("database.port = x")}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := tc.source.ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
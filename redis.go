@@ -0,0 +1,124 @@
+package primordius
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultRedisTimeout is the default time a Redis source may take to read its keys.
+const DefaultRedisTimeout = 5 * time.Second
+
+type (
+	// RedisClient is the subset of a Redis client required by the Redis sources.
+	// Wrap your client of choice to satisfy it, e.g. for go-redis:
+	//
+	//	func (a adapter) Get(ctx context.Context, key string) (string, error) {
+	//		val, err := a.c.Get(ctx, key).Result()
+	//		if errors.Is(err, redis.Nil) {
+	//			return "", primordius.ErrKeyNotFound
+	//		}
+	//		return val, err
+	//	}
+	RedisClient interface {
+		// Get returns the value stored at key, or an error wrapping ErrKeyNotFound
+		// if there is none.
+		Get(ctx context.Context, key string) (string, error)
+	}
+	redisSource struct {
+		client RedisClient
+		key    string
+		format Format
+		conv   *converter
+	}
+	redisKeysSource struct {
+		client RedisClient
+		prefix string
+		conv   *converter
+
+		ctx context.Context
+		// err is the first error reading a key other than a missing key.
+		err error
+	}
+)
+
+func (rs *redisSource) ToTarget(t any) error {
+	return decodeSource(rs, t)
+}
+
+func (rs *redisSource) describe() string {
+	return rs.key
+}
+
+func (rs *redisSource) load() ([]byte, Format, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), rs.conv.redisReadTimeout())
+	defer cancel()
+	val, err := rs.client.Get(ctx, rs.key)
+	return []byte(val), rs.format, err
+}
+
+func (rks *redisKeysSource) ToTarget(t any) error {
+	ctx, cancel := context.WithTimeout(context.Background(), rks.conv.redisReadTimeout())
+	defer cancel()
+	rks.ctx, rks.err = ctx, nil
+
+	if err := rks.conv.applyTagged(t, rks); err != nil {
+		return err
+	}
+	return rks.err
+}
+
+func (rks *redisKeysSource) describe() string {
+	return rks.prefix
+}
+
+func (rks *redisKeysSource) lookup(_, key string) (string, bool) {
+	if rks.err != nil {
+		return "", false
+	}
+	val, err := rks.client.Get(rks.ctx, rks.prefix+key)
+	if err != nil {
+		if !errors.Is(err, ErrKeyNotFound) {
+			rks.err = fmt.Errorf("reading Redis key %s: %w", rks.prefix+key, err)
+		}
+		return "", false
+	}
+	return val, true
+}
+
+// keys returns no keys as Redis keys are only read by the names of fields.
+func (rks *redisKeysSource) keys() []string {
+	return nil
+}
+
+// redisReadTimeout returns the time Redis sources may take to read their keys.
+func (c *converter) redisReadTimeout() time.Duration {
+	if c == nil || c.redisTimeout <= 0 {
+		return DefaultRedisTimeout
+	}
+	return c.redisTimeout
+}
+
+// SetRedisTimeout sets the time each Redis source may take to read its keys. A
+// non-positive timeout selects DefaultRedisTimeout.
+func (pr *Primordius) SetRedisTimeout(timeout time.Duration) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.conv.redisTimeout = timeout
+}
+
+// FromRedis adds a Source to pr which reads the value stored at key from Redis
+// and decodes it according to format.
+func (pr *Primordius) FromRedis(client RedisClient, key string, format Format) {
+	pr.AddSource(&redisSource{client: client, key: key, format: format, conv: pr.conv})
+}
+
+// FromRedisKeys adds a Source to pr which reads the values of fields from the Redis keys
+// consisting of keyPrefix and the key from the env tag of each field, e.g. "my-app:PORT"
+// for the prefix "my-app:" and `env:"PORT"`. Values are converted like those of
+// environment variables. Missing keys are skipped; other errors fail Process. Wildcard
+// keys like `env:"ROUTE_*"` are not supported. See SetRedisTimeout.
+func (pr *Primordius) FromRedisKeys(client RedisClient, keyPrefix string) {
+	pr.AddSource(&redisKeysSource{client: client, prefix: keyPrefix, conv: pr.conv})
+}
@@ -0,0 +1,111 @@
+package primordius
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeRedis map[string]string
+
+func (fr fakeRedis) Get(ctx context.Context, key string) (string, error) {
+	if key == "slow:HOST" {
+		<-ctx.Done()
+		return "", ctx.Err()
+	}
+	if key == "broken:PORT" {
+		return "", errors.New("redis: connection reset")
+	}
+	v, ok := fr[key]
+	if !ok {
+		return "", ErrKeyNotFound
+	}
+	return v, nil
+}
+
+func Test_redisSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+		Port int    `json:"port" yaml:"port" toml:"port"`
+	}
+
+	client := fakeRedis{
+		"json": `{"host": "localhost", "port": 6379}`,
+		"yaml": "host: localhost\nport: 6379",
+		"toml": "host = \"localhost\"\nport = 6379",
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		want    target
+		wantErr bool
+	}{
+		{"JSON value", &redisSource{client: client, key: "json", format: FormatJSON}, target{"localhost", 6379}, false},
+		{"YAML value", &redisSource{client: client, key: "yaml", format: FormatYAML}, target{"localhost", 6379}, false},
+		{"TOML value", &redisSource{client: client, key: "toml", format: FormatTOML}, target{"localhost", 6379}, false},
+		{"missing key", &redisSource{client: client, key: "nope", format: FormatJSON}, target{}, true},
+		{"unknown format", &redisSource{client: client, key: "json", format: "xml"}, target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := tc.source.ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_redisKeysSource_ToTarget(t *testing.T) {
+	type target struct {
+		Host    string        `env:"HOST"`
+		Port    int           `env:"PORT"`
+		Timeout time.Duration `env:"TIMEOUT"`
+	}
+
+	client := fakeRedis{
+		"app:HOST":     "localhost",
+		"app:PORT":     "6379",
+		"invalid:PORT": "high",
+		"broken:HOST":  "localhost",
+	}
+
+	tests := []struct {
+		name    string
+		prefix  string
+		timeout time.Duration
+		want    target
+		wantErr error
+	}{
+		{"keys", "app:", 0, target{Host: "localhost", Port: 6379}, nil},
+		{"no keys", "other:", 0, target{}, nil},
+		{"invalid value", "invalid:", 0, target{}, errAny},
+		{"read error", "broken:", 0, target{Host: "localhost"}, errAny},
+		{"timeout", "slow:", 10 * time.Millisecond, target{}, context.DeadlineExceeded},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			rks := &redisKeysSource{client: client, prefix: tc.prefix, conv: &converter{redisTimeout: tc.timeout}}
+			err := rks.ToTarget(&got)
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("ToTarget() error = %v, want nil", err)
+			case tc.wantErr == errAny && err == nil:
+				t.Fatalf("ToTarget() error = nil, want an error")
+			case tc.wantErr != nil && tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+				t.Fatalf("ToTarget() error = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
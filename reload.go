@@ -0,0 +1,50 @@
+package primordius
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Reload processes all sources into a copy of the target and, if that succeeds,
+// replaces the values of the target with those of the copy in a single step. It
+// returns the sorted dotted paths of the fields whose values changed, e.g. for
+// logging. If processing fails, the target is left untouched.
+func (pr *Primordius) Reload() (changed []string, err error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if err := checkTarget(pr.target); err != nil {
+		return nil, err
+	}
+	tv := reflect.ValueOf(pr.target)
+
+	tmp := reflect.New(tv.Elem().Type())
+	c, err := deepCopy(tv.Elem())
+	if err != nil {
+		return nil, err
+	}
+	tmp.Elem().Set(c)
+	if err := pr.process(tmp.Interface()); err != nil {
+		return nil, err
+	}
+
+	before, err := takeSnapshot(pr.target)
+	if err != nil {
+		return nil, err
+	}
+	after, err := takeSnapshot(tmp.Interface())
+	if err != nil {
+		return nil, err
+	}
+	changed = after.changed(before)
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+	sort.Strings(changed)
+
+	tv.Elem().Set(tmp.Elem())
+	pr.fingerprint = after
+
+	return changed, nil
+}
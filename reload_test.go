@@ -0,0 +1,61 @@
+package primordius
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestPrimordius_Reload(t *testing.T) {
+	type database struct {
+		Host string `json:"host"`
+	}
+	type target struct {
+		Port     int       `json:"port"`
+		Debug    bool      `json:"debug"`
+		Database *database `json:"database"`
+	}
+	name := filepath.Join(t.TempDir(), "config.json")
+
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{"initial", `{"port": 80}`, []string{"Port"}, false},
+		{"unchanged", `{"port": 80}`, []string{}, false},
+		{"changed and added", `{"port": 81, "database": {"host": "db"}}`, []string{"Database", "Database.Host", "Port"}, false},
+		{"invalid content", `{"port": "eighty"}`, nil, true},
+		{"boolean", `{"port": 81, "debug": true, "database": {"host": "db"}}`, []string{"Debug"}, false},
+	}
+
+	var got target
+	pr := New(&got)
+	pr.FromJSONFile(name)
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := os.WriteFile(name, []byte(tc.content), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			before := got
+			changed, err := pr.Reload()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Reload() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil {
+				if !reflect.DeepEqual(got, before) {
+					t.Errorf("Reload() modified target on error: got = %+v, want %+v", got, before)
+				}
+				return
+			}
+			if !reflect.DeepEqual(changed, tc.want) {
+				t.Errorf("Reload() changed = %v, want %v", changed, tc.want)
+			}
+			if err := pr.AssertUnchanged(); err != nil {
+				t.Errorf("AssertUnchanged() error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package primordius
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"text/tabwriter"
+)
+
+const redacted = "[REDACTED]"
+
+// Report describes which fields the sources changed during Process.
+type Report struct {
+	// Origins maps the path of each field of the target, e.g. "Database.Host", to the
+	// source which set it last, e.g. "#1 envSource", or "default" if no source changed it.
+	Origins map[string]string
+	// Touched maps each source, described like in Origins, to the sorted paths of the
+	// fields it changed, even if a later source overrode them.
+	Touched map[string][]string
+}
+
+// ProcessReport calls Process and returns a Report of the fields the sources changed.
+func (pr *Primordius) ProcessReport() (Report, error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	if err := pr.processTarget(); err != nil {
+		return Report{}, err
+	}
+
+	r := Report{
+		Origins: make(map[string]string),
+		Touched: make(map[string][]string, len(pr.trace.labels)),
+	}
+	ss, err := takeSnapshot(pr.target)
+	if err != nil {
+		return Report{}, err
+	}
+	for _, path := range ss.paths() {
+		r.Origins[path] = pr.trace.origin(path)
+	}
+	for i := range pr.trace.labels {
+		r.Touched[pr.trace.source(i)] = append([]string{}, pr.trace.touched[i]...)
+	}
+
+	return r, nil
+}
+
+// PrecedenceReport writes a table to w listing, for each field of the target, its
+// final value and the source which set it last during the most recent call to
+// Process. Fields no source changed are reported with the origin "default".
+// Values of fields tagged with `secret:"true"` are redacted.
+func (pr *Primordius) PrecedenceReport(w io.Writer) error {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	if pr.trace == nil {
+		return ErrNotProcessed
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "FIELD\tVALUE\tSOURCE")
+	ss, err := takeSnapshot(pr.target)
+	if err != nil {
+		return err
+	}
+	for _, path := range ss.paths() {
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", path, formatValue(ss[path]), pr.trace.origin(path))
+	}
+
+	return tw.Flush()
+}
+
+// provenance is the JSON representation of a field in ProvenanceJSON.
+type provenance struct {
+	Field       string          `json:"field"`
+	Value       json.RawMessage `json:"value"`
+	Source      string          `json:"source"`
+	SourceIndex int             `json:"sourceIndex"`
+	Redacted    bool            `json:"redacted,omitempty"`
+}
+
+// ProvenanceJSON writes the information of PrecedenceReport as a JSON array to w, for
+// consumption by tools. Each element describes a field like
+//
+//	{"field": "Port", "value": 9090, "source": "envSource", "sourceIndex": 1}
+//
+// Fields no source changed have the source "default" and the sourceIndex -1. Values of
+// fields tagged with `secret:"true"` are replaced by "[REDACTED]" and marked with
+// "redacted": true. Values which cannot be represented in JSON are written as strings.
+func (pr *Primordius) ProvenanceJSON(w io.Writer) error {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+	if pr.trace == nil {
+		return ErrNotProcessed
+	}
+
+	ss, err := takeSnapshot(pr.target)
+	if err != nil {
+		return err
+	}
+	fields := make([]provenance, 0, len(ss))
+	for _, path := range ss.paths() {
+		p := provenance{Field: path, Source: "default", SourceIndex: -1, Redacted: ss[path].secret}
+		if i, ok := pr.trace.origins[path]; ok {
+			p.Source, p.SourceIndex = pr.trace.labels[i], i
+		}
+		value, err := json.Marshal(ss[path].value)
+		if err != nil || p.Redacted {
+			value, _ = json.Marshal(formatValue(ss[path]))
+		}
+		p.Value = value
+		fields = append(fields, p)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(fields)
+}
+
+// formatValue returns the printable value of fs, dereferencing pointers and
+// redacting secrets.
+func formatValue(fs fieldSnapshot) string {
+	if fs.secret {
+		return redacted
+	}
+	v := reflect.ValueOf(fs.value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "<nil>"
+		}
+		v = v.Elem()
+	}
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	return fmt.Sprintf("%v", v.Interface())
+}
@@ -0,0 +1,154 @@
+package primordius
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPrimordius_PrecedenceReport(t *testing.T) {
+	type database struct {
+		Host string `yaml:"host"`
+	}
+	target := struct {
+		Port     int      `yaml:"port" env:"PORT"`
+		Name     string   `yaml:"name"`
+		Password string   `env:"DB_PASSWORD" secret:"true"`
+		Database database `yaml:"database"`
+	}{Name: "app"}
+
+	if err := os.Setenv("REPORT_PORT", "9090"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("REPORT_PORT")
+	if err := os.Setenv("REPORT_DB_PASSWORD", "hunter2"); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("REPORT_DB_PASSWORD")
+
+	pr := New(&target)
+	var buf bytes.Buffer
+	if err := pr.PrecedenceReport(&buf); err != ErrNotProcessed {
+		t.Errorf("PrecedenceReport() error = %v, want %v", err, ErrNotProcessed)
+	}
+
+	pr.FromYAML([]byte("port: 8080\ndatabase:\n  host: db.local"))
+	pr.FromEnv("REPORT_")
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if err := pr.PrecedenceReport(&buf); err != nil {
+		t.Fatalf("PrecedenceReport() error = %v", err)
+	}
+
+	report := buf.String()
+	for _, want := range []string{
+		"Database.Host  db.local    #0 yamlContentSource",
+		"Name           app         default",
+		"Password       [REDACTED]  #1 envSource",
+		"Port           9090        #1 envSource",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("PrecedenceReport() missing line %q in\n%s", want, report)
+		}
+	}
+	if strings.Contains(report, "hunter2") {
+		t.Errorf("PrecedenceReport() leaks secret value:\n%s", report)
+	}
+}
+
+func TestPrimordius_ProvenanceJSON(t *testing.T) {
+	target := struct {
+		Port     int           `yaml:"port"`
+		Name     string        `yaml:"name"`
+		Password string        `yaml:"password" secret:"true"`
+		Tags     []string      `yaml:"tags"`
+		Notify   chan struct{} `yaml:"-"`
+	}{Name: "app"}
+
+	pr := New(&target)
+	var buf bytes.Buffer
+	if err := pr.ProvenanceJSON(&buf); err != ErrNotProcessed {
+		t.Errorf("ProvenanceJSON() error = %v, want %v", err, ErrNotProcessed)
+	}
+
+	pr.FromYAML([]byte("port: 8080\npassword: hunter2"))
+	pr.FromYAML([]byte("tags: [a, b]"))
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if err := pr.ProvenanceJSON(&buf); err != nil {
+		t.Fatalf("ProvenanceJSON() error = %v", err)
+	}
+
+	var got []map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("ProvenanceJSON() wrote invalid JSON: %v\n%s", err, buf.String())
+	}
+	want := []map[string]any{
+		{"field": "Name", "value": "app", "source": "default", "sourceIndex": -1.0},
+		{"field": "Notify", "value": "<nil>", "source": "default", "sourceIndex": -1.0},
+		{"field": "Password", "value": "[REDACTED]", "source": "yamlContentSource", "sourceIndex": 0.0, "redacted": true},
+		{"field": "Port", "value": 8080.0, "source": "yamlContentSource", "sourceIndex": 0.0},
+		{"field": "Tags", "value": []any{"a", "b"}, "source": "yamlContentSource", "sourceIndex": 1.0},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ProvenanceJSON() = %v, want %v", got, want)
+	}
+	if strings.Contains(buf.String(), "hunter2") {
+		t.Errorf("ProvenanceJSON() leaks secret value:\n%s", buf.String())
+	}
+}
+
+func TestPrimordius_ProcessReport(t *testing.T) {
+	type target struct {
+		Host string `yaml:"host" env:"HOST"`
+		Port int    `yaml:"port"`
+		Name string
+	}
+	t.Setenv("PROCESS_REPORT_HOST", "env.local")
+
+	tests := []struct {
+		name    string
+		yaml    string
+		want    Report
+		wantErr bool
+	}{
+		{
+			name: "env overrides yaml",
+			yaml: "host: yaml.local\nport: 8080",
+			want: Report{
+				Origins: map[string]string{"Host": "#1 envSource", "Port": "#0 yamlContentSource", "Name": "default"},
+				Touched: map[string][]string{"#0 yamlContentSource": {"Host", "Port"}, "#1 envSource": {"Host"}},
+			},
+		},
+		{
+			name: "source changing nothing",
+			yaml: "{}",
+			want: Report{
+				Origins: map[string]string{"Host": "#1 envSource", "Port": "default", "Name": "default"},
+				Touched: map[string][]string{"#0 yamlContentSource": {}, "#1 envSource": {"Host"}},
+			},
+		},
+		{name: "failing source", yaml: "port: [", wantErr: true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var cfg target
+			pr := New(&cfg)
+			pr.FromYAML([]byte(tc.yaml))
+			pr.FromEnv("PROCESS_REPORT_")
+			got, err := pr.ProcessReport()
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ProcessReport() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("ProcessReport() got = %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
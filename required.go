@@ -0,0 +1,144 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	requiredTagName   = "required"
+	minSourcesTagName = "minsources"
+)
+
+var (
+	ErrMissingRequired = errors.New("required fields are missing")
+	ErrTooFewSources   = errors.New("fields were not set by enough sources")
+)
+
+// checkRequired returns an error wrapping ErrMissingRequired and listing all fields of
+// the struct target points to which are marked as required but zero.
+func checkRequired(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	missing := missingRequired(v.Elem(), "", make([]string, 0))
+	if len(missing) > 0 {
+		return fmt.Errorf("%w: %s", ErrMissingRequired, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// missingRequired appends the paths of the zero required fields of the struct s to
+// missing, descending into nested structs, non-nil pointers to them and slices of them.
+func missingRequired(s reflect.Value, path string, missing []string) []string {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := s.Field(i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		_, opts := parseTag(sf.Tag.Get(tagName))
+		if isRequired(sf, opts) && isZero(f) {
+			missing = append(missing, fieldPath)
+			continue
+		}
+		missing = missingNested(f, fieldPath, missing)
+	}
+
+	return missing
+}
+
+// isRequired reports whether the field sf, whose env tag has the options opts, is
+// marked as required.
+func isRequired(sf reflect.StructField, opts tagOptions) bool {
+	return opts.has("required") || sf.Tag.Get(requiredTagName) == "true"
+}
+
+func missingNested(v reflect.Value, path string, missing []string) []string {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return missing
+		}
+		return missingNested(v.Elem(), path, missing)
+	case reflect.Struct:
+		return missingRequired(v, path, missing)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			missing = missingNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i), missing)
+		}
+	}
+
+	return missing
+}
+
+// checkMinSources returns an error wrapping ErrTooFewSources and listing all fields of
+// the struct target points to which were changed by fewer sources than required by
+// their minsources tag, according to the trace of the last run.
+func (pr *Primordius) checkMinSources(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	violations := make([]string, 0)
+	err := walkMinSources(v.Elem(), "", func(path string, min int) {
+		if n := pr.trace.sourceCount(path); n < min {
+			violations = append(violations, fmt.Sprintf("%s (%d of %d)", path, n, min))
+		}
+	})
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return fmt.Errorf("%w: %s", ErrTooFewSources, strings.Join(violations, ", "))
+	}
+	return nil
+}
+
+// walkMinSources calls fn for every field of the struct s with a minsources tag,
+// descending into nested structs and non-nil pointers to them.
+func walkMinSources(s reflect.Value, path string, fn func(path string, min int)) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		if tagVal := sf.Tag.Get(minSourcesTagName); tagVal != "" {
+			min, err := strconv.Atoi(tagVal)
+			if err != nil {
+				return fmt.Errorf("field %s: invalid %s tag: %w", fieldPath, minSourcesTagName, err)
+			}
+			fn(fieldPath, min)
+			continue
+		}
+		f := s.Field(i)
+		if f.Kind() == reflect.Pointer && !f.IsNil() {
+			f = f.Elem()
+		}
+		if hasExportedFields(f.Type()) {
+			if err := walkMinSources(f, fieldPath, fn); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
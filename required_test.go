@@ -0,0 +1,93 @@
+package primordius
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestPrimordius_Process_required(t *testing.T) {
+	type database struct {
+		Host string `json:"host" required:"true"`
+	}
+	type target struct {
+		Port     int        `json:"port" env:"PORT,required"`
+		Name     string     `json:"name" required:"true"`
+		Debug    bool       `json:"debug" required:"false"`
+		Database database   `json:"database"`
+		Replica  *database  `json:"replica"`
+		Shards   []database `json:"shards"`
+	}
+
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"all set", `{"port": 80, "name": "app", "database": {"host": "db"}}`, ""},
+		{"all missing", `{}`, "required fields are missing: Port, Name, Database.Host"},
+		{"nested missing", `{"port": 80, "name": "app", "database": {"host": "db"}, "replica": {}, "shards": [{"host": "a"}, {}]}`,
+			"required fields are missing: Replica.Host, Shards[1].Host"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.FromJSON([]byte(tc.content))
+			err := pr.Process()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrMissingRequired) || err.Error() != tc.wantErr {
+				t.Errorf("Process() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestPrimordius_Process_minSources(t *testing.T) {
+	type database struct {
+		Host string `json:"host"`
+	}
+	type target struct {
+		Port     int      `json:"port" env:"PORT" minsources:"1"`
+		Token    string   `env:"TOKEN" minsources:"2"`
+		Database database `json:"database" minsources:"1"`
+	}
+
+	tests := []struct {
+		name    string
+		env     map[string]string
+		content string
+		wantErr string
+	}{
+		{"all set", map[string]string{"MS_TOKEN": "t"}, `{"port": 80, "token": "x", "database": {"host": "db"}}`, ""},
+		{"compiled defaults only", nil, `{}`, "fields were not set by enough sources: Port (0 of 1), Token (0 of 2), Database (0 of 1)"},
+		{"too few sources", map[string]string{"MS_PORT": "81"}, `{"database": {"host": "db"}}`, "fields were not set by enough sources: Token (0 of 2)"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			for k, v := range tc.env {
+				t.Setenv(k, v)
+			}
+			got := target{Port: 8080, Token: "default", Database: database{Host: "localhost"}}
+			pr := New(&got)
+			pr.FromJSON([]byte(tc.content))
+			pr.FromEnv("MS")
+			err := pr.Process()
+			if tc.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Process() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrTooFewSources) || err.Error() != tc.wantErr {
+				t.Errorf("Process() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
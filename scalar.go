@@ -0,0 +1,39 @@
+package primordius
+
+import (
+	"fmt"
+	"reflect"
+)
+
+type scalarSource struct {
+	field string
+	value string
+}
+
+func (ss *scalarSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+	f, err := fieldByPath(v.Elem(), ss.field, true)
+	if err != nil {
+		return err
+	}
+	if err := setValue(f, ss.value); err != nil {
+		return fmt.Errorf("field %s: %w", ss.field, err)
+	}
+
+	return nil
+}
+
+func (ss *scalarSource) describe() string {
+	return ss.field
+}
+
+// FromScalar adds a Source to pr which sets the single field at the dotted path field,
+// e.g. "License.Key", to value, converted according to the type of the field just like
+// values from env vars. This allows injecting a computed value at a specific position
+// in the order of sources.
+func (pr *Primordius) FromScalar(field string, value string) {
+	pr.AddSource(&scalarSource{field: field, value: value})
+}
@@ -0,0 +1,40 @@
+package primordius
+
+import "testing"
+
+func Test_scalarSource_ToTarget(t *testing.T) {
+	type license struct {
+		Key   string
+		Seats int
+	}
+	type target struct {
+		Debug   bool
+		License *license
+	}
+
+	tests := []struct {
+		name    string
+		field   string
+		value   string
+		want    target
+		wantErr bool
+	}{
+		{"top-level field", "Debug", "true", target{Debug: true}, false},
+		{"nested field", "License.Seats", "25", target{License: &license{Seats: 25}}, false},
+		{"invalid value", "License.Seats", "many", target{License: &license{}}, true},
+		{"unknown field", "License.Owner", "me", target{License: &license{}}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&scalarSource{field: tc.field, value: tc.value}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got.Debug != tc.want.Debug || (got.License == nil) != (tc.want.License == nil) ||
+				(got.License != nil && *got.License != *tc.want.License) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package primordius
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// SecretScheme is the prefix marking a string value as a reference to a secret
+// which is replaced by the registered secret resolver during Process.
+const SecretScheme = "secret://"
+
+// SecretResolver returns the secret value referenced by ref, e.g. "secret://db-password".
+type SecretResolver func(ref string) (string, error)
+
+// SetSecretResolver registers fn to resolve secret references. After all sources were
+// processed, every string value in the target starting with SecretScheme is replaced
+// by the result of fn, regardless of which source provided the reference.
+// Supply nil to disable resolving.
+func (pr *Primordius) SetSecretResolver(fn SecretResolver) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.secretResolver = fn
+}
+
+// resolveSecrets replaces all secret references in target using pr.secretResolver.
+func (pr *Primordius) resolveSecrets(target any) error {
+	if pr.secretResolver == nil {
+		return nil
+	}
+
+	return walkStrings(reflect.ValueOf(target), "", make(map[visit]bool), func(path, val string) (string, error) {
+		if !strings.HasPrefix(val, SecretScheme) {
+			return val, nil
+		}
+		secret, err := pr.secretResolver(val)
+		if err != nil {
+			return "", fmt.Errorf("field %s: resolving secret: %w", path, err)
+		}
+		return secret, nil
+	})
+}
+
+// walkStrings calls fn for every settable string reachable from v, i.e. string
+// fields of (nested) structs, pointers, slices and arrays as well as string map
+// values, and replaces each string with the returned value. Pointers, slices and maps
+// already being walked are tracked in visited; a cycle results in an error wrapping
+// ErrPointerCycle.
+func walkStrings(v reflect.Value, path string, visited map[visit]bool, fn func(path, val string) (string, error)) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		if v.IsNil() || v.Kind() == reflect.Slice && v.Len() == 0 {
+			return nil
+		}
+		vi := visit{typ: v.Type(), ptr: v.Pointer()}
+		if visited[vi] {
+			return fmt.Errorf("%w: field %s", ErrPointerCycle, path)
+		}
+		visited[vi] = true
+		defer delete(visited, vi)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return walkStrings(v.Elem(), path, visited, fn)
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < v.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			fieldPath := t.Field(i).Name
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if err := walkStrings(v.Field(i), fieldPath, visited, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := walkStrings(v.Index(i), fmt.Sprintf("%s[%d]", path, i), visited, fn); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if v.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			val, err := fn(fmt.Sprintf("%s[%v]", path, iter.Key()), iter.Value().String())
+			if err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), reflect.ValueOf(val).Convert(v.Type().Elem()))
+		}
+	case reflect.String:
+		if !v.CanSet() {
+			return nil
+		}
+		val, err := fn(path, v.String())
+		if err != nil {
+			return err
+		}
+		v.SetString(val)
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package primordius
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPrimordius_SetSecretResolver(t *testing.T) {
+	type db struct {
+		Password string `yaml:"password"`
+	}
+	var target struct {
+		User  string   `yaml:"user"`
+		DB    db       `yaml:"db"`
+		Keys  []string `yaml:"keys"`
+		Plain string   `yaml:"plain"`
+	}
+	secrets := map[string]string{"pw": "hunter2", "k1": "key-1"}
+
+	pr := New(&target)
+	pr.FromYAML([]byte("user: admin\ndb:\n  password: secret://pw\nkeys: [secret://k1, raw]\nplain: secret-ish"))
+	pr.SetSecretResolver(func(ref string) (string, error) {
+		v, ok := secrets[strings.TrimPrefix(ref, SecretScheme)]
+		if !ok {
+			return "", errors.New("not found")
+		}
+		return v, nil
+	})
+
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if target.DB.Password != "hunter2" {
+		t.Errorf("DB.Password = %q, want %q", target.DB.Password, "hunter2")
+	}
+	if target.Keys[0] != "key-1" || target.Keys[1] != "raw" {
+		t.Errorf("Keys = %v, want [key-1 raw]", target.Keys)
+	}
+	if target.User != "admin" || target.Plain != "secret-ish" {
+		t.Errorf("unrelated fields were modified: %+v", target)
+	}
+
+	pr.ResetSources()
+	pr.FromYAML([]byte("user: secret://unknown"))
+	if err := pr.Process(); err == nil {
+		t.Error("Process() error = nil, want error for unresolvable secret")
+	}
+}
@@ -0,0 +1,66 @@
+package primordius
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+type sqlSource struct {
+	db    *sql.DB
+	query string
+	conv  *converter
+}
+
+func (ss *sqlSource) ToTarget(t any) error {
+	values, err := ss.values(context.Background())
+	if err != nil {
+		return err
+	}
+
+	return ss.conv.applyTagged(t, values)
+}
+
+func (ss *sqlSource) describe() string {
+	return ss.query
+}
+
+// values runs the query and returns the values of the rows by key. Rows with a NULL
+// value are skipped; of rows with the same key, the last one wins.
+func (ss *sqlSource) values(ctx context.Context) (valueMap, error) {
+	rows, err := ss.db.QueryContext(ctx, ss.query)
+	if err != nil {
+		return nil, fmt.Errorf("querying configuration: %w", err)
+	}
+	defer rows.Close()
+
+	values := make(valueMap)
+	for row := 1; rows.Next(); row++ {
+		var (
+			key string
+			val sql.NullString
+		)
+		if err := rows.Scan(&key, &val); err != nil {
+			return nil, fmt.Errorf("scanning row %d of configuration query: %w", row, err)
+		}
+		if val.Valid {
+			values[key] = val.String
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("reading configuration rows: %w", err)
+	}
+
+	return values, nil
+}
+
+// FromSQL adds a Source to pr which runs query on db, e.g.
+//
+//	SELECT name, value FROM settings WHERE app = 'my-app'
+//
+// The query must return two columns, a key and a value, per row. The keys are matched
+// against the 'env' tag and values are converted like those of environment variables;
+// NULL values are skipped. The query runs once per call to Process.
+func (pr *Primordius) FromSQL(db *sql.DB, query string) {
+	pr.AddSource(&sqlSource{db: db, query: query, conv: pr.conv})
+}
@@ -0,0 +1,114 @@
+package primordius
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+)
+
+// fakeSQLDriver serves the rows of a fixed table per query. Each row is a slice of
+// column values.
+type (
+	fakeSQLDriver map[string][][]driver.Value
+	fakeSQLConn   struct{ tables fakeSQLDriver }
+	fakeSQLStmt   struct {
+		rows [][]driver.Value
+		ok   bool
+	}
+	fakeSQLRows struct {
+		rows [][]driver.Value
+		pos  int
+	}
+)
+
+func init() {
+	sql.Register("primordius-fake", fakeSQLDriver{
+		"settings": {{"host", "db.local"}, {"port", "5432"}, {"debug", nil}, {"port", int64(5433)}},
+		"invalid":  {{"port", "high"}},
+		"columns":  {{"host"}},
+	})
+}
+
+func (fd fakeSQLDriver) Open(string) (driver.Conn, error) { return &fakeSQLConn{tables: fd}, nil }
+
+func (fc *fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	rows, ok := fc.tables[query]
+	return &fakeSQLStmt{rows: rows, ok: ok}, nil
+}
+
+func (fc *fakeSQLConn) Close() error { return nil }
+
+func (fc *fakeSQLConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (fs *fakeSQLStmt) Close() error { return nil }
+
+func (fs *fakeSQLStmt) NumInput() int { return 0 }
+
+func (fs *fakeSQLStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+
+func (fs *fakeSQLStmt) Query([]driver.Value) (driver.Rows, error) {
+	if !fs.ok {
+		return nil, errors.New("no such table")
+	}
+	return &fakeSQLRows{rows: fs.rows}, nil
+}
+
+func (fr *fakeSQLRows) Columns() []string {
+	if len(fr.rows) > 0 && len(fr.rows[0]) != 2 {
+		return []string{"name"}
+	}
+	return []string{"name", "value"}
+}
+
+func (fr *fakeSQLRows) Close() error { return nil }
+
+func (fr *fakeSQLRows) Next(dest []driver.Value) error {
+	if fr.pos >= len(fr.rows) {
+		return io.EOF
+	}
+	copy(dest, fr.rows[fr.pos])
+	fr.pos++
+	return nil
+}
+
+func Test_sqlSource_ToTarget(t *testing.T) {
+	db, err := sql.Open("primordius-fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	type target struct {
+		Host  string `env:"host"`
+		Port  int    `env:"port"`
+		Debug bool   `env:"debug"`
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		want    target
+		wantErr bool
+	}{
+		{"rows", "settings", target{Host: "db.local", Port: 5433}, false},
+		{"invalid value", "invalid", target{}, true},
+		{"wrong number of columns", "columns", target{}, true},
+		{"query error", "missing", target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			if err := (&sqlSource{db: db, query: tc.query}).ToTarget(&got); (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,90 @@
+package primordius
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// subtreeSource decodes only the section of the content of source found under a
+// dotted key path.
+type subtreeSource struct {
+	source loadingSource
+	path   string
+}
+
+func (ss *subtreeSource) ToTarget(t any) error {
+	return decodeSource(ss, t)
+}
+
+func (ss *subtreeSource) describe() string {
+	return sourceDescription(ss.source) + " at " + ss.path
+}
+
+func (ss *subtreeSource) modTime() time.Time {
+	if ws, ok := ss.source.(watchedSource); ok {
+		return ws.modTime()
+	}
+	return time.Time{}
+}
+
+func (ss *subtreeSource) load() ([]byte, Format, error) {
+	cont, format, err := ss.source.load()
+	if err != nil || cont == nil {
+		return cont, format, err
+	}
+	sub, err := extractSubtree(format, cont, ss.path)
+	return sub, format, err
+}
+
+// extractSubtree returns the section of content found under the dotted key path, e.g.
+// "a.b.c", encoded in format again. Keys are matched case-insensitively, preferring
+// exact matches.
+func extractSubtree(format Format, content []byte, path string) ([]byte, error) {
+	fn, ok := codec(format)
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownFormat, format)
+	}
+	encode, ok := encoders[format]
+	if !ok {
+		return nil, fmt.Errorf("key paths are not supported in format %q", format)
+	}
+
+	node, err := decodeMap(format, fn, stripBOM(content))
+	if err != nil {
+		return nil, &decodeError{err: err}
+	}
+	keys := strings.Split(path, ".")
+	for i, key := range keys {
+		v, ok := lookupFold(node, key)
+		if !ok {
+			return nil, fmt.Errorf("%w: %s of key path %q", ErrKeyNotFound, strings.Join(keys[:i+1], "."), path)
+		}
+		if node, ok = v.(map[string]any); !ok {
+			return nil, fmt.Errorf("key path %q: %s is not a map", path, strings.Join(keys[:i+1], "."))
+		}
+	}
+
+	return encode(node)
+}
+
+// FromYAMLFileAt adds a Source to pr which reads values from the section of a YAML
+// file found under the dotted key path, e.g. "service" or "apps.api", as if it was
+// the whole file, so the target needs no wrapper struct for the enclosing keys. Keys
+// are matched case-insensitively. It is an error if the path doesn't exist or doesn't
+// lead to a map.
+func (pr *Primordius) FromYAMLFileAt(name, path string) {
+	pr.AddSource(&subtreeSource{source: &yamlFileSource{name: name}, path: path})
+}
+
+// FromJSONFileAt adds a Source to pr which reads values from the section of a JSON
+// file found under the dotted key path, like FromYAMLFileAt.
+func (pr *Primordius) FromJSONFileAt(name, path string) {
+	pr.AddSource(&subtreeSource{source: &jsonFileSource{name: name}, path: path})
+}
+
+// FromTOMLFileAt adds a Source to pr which reads values from the table of a TOML
+// file found under the dotted key path, like FromYAMLFileAt.
+func (pr *Primordius) FromTOMLFileAt(name, path string) {
+	pr.AddSource(&subtreeSource{source: &tomlFileSource{name: name}, path: path})
+}
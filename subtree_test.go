@@ -0,0 +1,103 @@
+package primordius
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestPrimordius_FromFileAt(t *testing.T) {
+	type target struct {
+		Host string `json:"host" yaml:"host" toml:"host"`
+		Port int    `json:"port" yaml:"port" toml:"port"`
+	}
+
+	files := map[string]string{
+		"app.yaml": "service:\n  host: example.com\n  port: 8080\n  limits:\n    port: 9090\nport: 1\n",
+		"app.json": `{"Apps": {"api": {"host": "example.com", "port": 8080}}, "name": "x"}`,
+		"app.toml": "[apps.api]\nhost = \"example.com\"\nport = 8080\n",
+	}
+	dir := t.TempDir()
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write test file: %s", err.Error())
+		}
+	}
+
+	tests := []struct {
+		name    string
+		add     func(pr *Primordius, name, path string)
+		file    string
+		path    string
+		want    target
+		wantErr error
+	}{
+		{"YAML top-level key", (*Primordius).FromYAMLFileAt, "app.yaml", "service", target{"example.com", 8080}, nil},
+		{"YAML nested key", (*Primordius).FromYAMLFileAt, "app.yaml", "service.limits", target{Port: 9090}, nil},
+		{"JSON case-insensitive path", (*Primordius).FromJSONFileAt, "app.json", "apps.API", target{"example.com", 8080}, nil},
+		{"TOML table", (*Primordius).FromTOMLFileAt, "app.toml", "apps.api", target{"example.com", 8080}, nil},
+		{"missing key", (*Primordius).FromYAMLFileAt, "app.yaml", "service.database", target{}, ErrKeyNotFound},
+		{"no map", (*Primordius).FromYAMLFileAt, "app.yaml", "service.host", target{}, errAny},
+		{"invalid content", (*Primordius).FromJSONFileAt, "app.yaml", "service", target{}, ErrDecode},
+		{"missing file", (*Primordius).FromTOMLFileAt, "missing.toml", "apps", target{}, os.ErrNotExist},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			tc.add(pr, filepath.Join(dir, tc.file), tc.path)
+			err := pr.Process()
+			switch {
+			case tc.wantErr == nil && err != nil:
+				t.Fatalf("Process() error = %v", err)
+			case tc.wantErr == errAny && err == nil:
+				t.Fatal("Process() error = nil, want error")
+			case tc.wantErr != nil && tc.wantErr != errAny && !errors.Is(err, tc.wantErr):
+				t.Fatalf("Process() error = %v, want %v", err, tc.wantErr)
+			}
+			if got != tc.want {
+				t.Errorf("Process() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_FromJSONFileAt_numbers(t *testing.T) {
+	type target struct {
+		Int  int64  `json:"int"`
+		Uint uint64 `json:"uint"`
+	}
+	name := filepath.Join(t.TempDir(), "app.json")
+	content := `{"svc": {"int": 9007199254740993, "uint": 18446744073709551615}}`
+	if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write test file: %s", err.Error())
+	}
+
+	var got target
+	pr := New(&got)
+	pr.FromJSONFileAt(name, "svc")
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+	if want := (target{Int: 9007199254740993, Uint: 18446744073709551615}); got != want {
+		t.Errorf("Process() got = %+v, want %+v", got, want)
+	}
+}
+
+func Test_subtreeSource_modTime(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(name, []byte("service:\n  port: 1\n"), 0600); err != nil {
+		t.Fatalf("failed to write test file: %s", err.Error())
+	}
+
+	var s Source = &subtreeSource{source: &yamlFileSource{name: name}, path: "service"}
+	ws, ok := s.(watchedSource)
+	if !ok {
+		t.Fatal("subtreeSource does not implement watchedSource")
+	}
+	if got, want := ws.modTime(), fileModTime(name); got.IsZero() || !got.Equal(want) {
+		t.Errorf("modTime() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+package primordius
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// tagOptions holds the options following the key in an env tag, e.g. "unescape"
+// in `env:"TLS_KEY,unescape"`. Options of the form name=value map name to value.
+type tagOptions map[string]string
+
+// parseTag splits an env tag value into the key and its options.
+func parseTag(tagVal string) (string, tagOptions) {
+	key, rest, _ := strings.Cut(tagVal, ",")
+	opts := make(tagOptions)
+	for _, opt := range strings.Split(rest, ",") {
+		if opt = strings.TrimSpace(opt); opt == "" {
+			continue
+		}
+		name, val, _ := strings.Cut(opt, "=")
+		opts[name] = val
+	}
+
+	return strings.TrimSpace(key), opts
+}
+
+// has reports whether the option name is present.
+func (to tagOptions) has(name string) bool {
+	_, ok := to[name]
+	return ok
+}
+
+// get returns the value of the option name, or def if it is absent or empty.
+func (to tagOptions) get(name, def string) string {
+	if val := to[name]; val != "" {
+		return val
+	}
+	return def
+}
+
+// withDefaults returns the options with those of defaults added which are absent.
+func (to tagOptions) withDefaults(defaults tagOptions) tagOptions {
+	opts := make(tagOptions, len(to)+len(defaults))
+	for name, val := range defaults {
+		opts[name] = val
+	}
+	for name, val := range to {
+		opts[name] = val
+	}
+	return opts
+}
+
+// transform applies the options modifying string values to val.
+func (to tagOptions) transform(val string) string {
+	if to.has("unquote") {
+		val = unquote(val)
+	}
+	if to.has("unescape") {
+		val = unescape(val)
+	}
+	return to.coerceCase(val)
+}
+
+// coerceCase converts val to upper or lower case if the respective option is present.
+func (to tagOptions) coerceCase(val string) string {
+	switch {
+	case to.has("upper"):
+		return strings.ToUpper(val)
+	case to.has("lower"):
+		return strings.ToLower(val)
+	}
+	return val
+}
+
+// coerceCases applies the upper and lower options of env tags to the string fields
+// of the struct v, found at path, descending into nested structs. This way, values
+// provided by sources other than env are normalized as well. A pointer cycle results
+// in an error wrapping ErrPointerCycle.
+func coerceCases(v reflect.Value, path string, visited map[visit]bool) error {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Pointer {
+			vi := visit{typ: v.Type(), ptr: v.Pointer()}
+			if visited[vi] {
+				return fmt.Errorf("%w: field %s", ErrPointerCycle, path)
+			}
+			visited[vi] = true
+			defer delete(visited, vi)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if !t.Field(i).IsExported() || !f.CanSet() {
+			continue
+		}
+		if f.Kind() != reflect.String {
+			fieldPath := t.Field(i).Name
+			if path != "" {
+				fieldPath = path + "." + fieldPath
+			}
+			if err := coerceCases(f, fieldPath, visited); err != nil {
+				return err
+			}
+			continue
+		}
+		_, opts := parseTag(t.Field(i).Tag.Get(tagName))
+		if val := f.String(); val != "" {
+			f.SetString(opts.coerceCase(val))
+		}
+	}
+
+	return nil
+}
+
+// unquote removes a single pair of matching single or double quotes surrounding s.
+func unquote(s string) string {
+	if len(s) >= 2 && (s[0] == '"' || s[0] == '\'') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1]
+	}
+	return s
+}
+
+// unescape replaces the escape sequences \n, \t and \\ in s by the characters they
+// represent. Other backslashes are kept as they are.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i == len(s)-1 {
+			sb.WriteByte(s[i])
+			continue
+		}
+		switch s[i+1] {
+		case 'n':
+			sb.WriteByte('\n')
+		case 't':
+			sb.WriteByte('\t')
+		case '\\':
+			sb.WriteByte('\\')
+		default:
+			sb.WriteByte(s[i])
+			continue
+		}
+		i++
+	}
+
+	return sb.String()
+}
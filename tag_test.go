@@ -0,0 +1,118 @@
+package primordius
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_parseTag(t *testing.T) {
+	tests := []struct {
+		tagVal   string
+		wantKey  string
+		wantOpts tagOptions
+	}{
+		{"", "", tagOptions{}},
+		{"PORT", "PORT", tagOptions{}},
+		{"TLS_KEY,unescape", "TLS_KEY", tagOptions{"unescape": ""}},
+		{"START, layout=2006-01-02 ,unescape", "START", tagOptions{"layout": "2006-01-02", "unescape": ""}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.tagVal, func(t *testing.T) {
+			key, opts := parseTag(tc.tagVal)
+			if key != tc.wantKey {
+				t.Errorf("parseTag() key = %q, want %q", key, tc.wantKey)
+			}
+			if !reflect.DeepEqual(opts, tc.wantOpts) {
+				t.Errorf("parseTag() opts = %v, want %v", opts, tc.wantOpts)
+			}
+		})
+	}
+}
+
+func Test_unescape(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{`-----BEGIN KEY-----\nabc\n-----END KEY-----`, "-----BEGIN KEY-----\nabc\n-----END KEY-----"},
+		{`a\tb`, "a\tb"},
+		{`C:\\temp`, `C:\temp`},
+		{`\\n`, `\n`},
+		{`keep \x and trailing \`, `keep \x and trailing \`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := unescape(tc.in); got != tc.want {
+				t.Errorf("unescape() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func Test_unquote(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"plain", "plain"},
+		{`"value"`, "value"},
+		{`'value'`, "value"},
+		{`""value""`, `"value"`},
+		{`"mismatched'`, `"mismatched'`},
+		{`"`, `"`},
+		{`""`, ""},
+		{`a "quoted" word`, `a "quoted" word`},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.in, func(t *testing.T) {
+			if got := unquote(tc.in); got != tc.want {
+				t.Errorf("unquote() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestPrimordius_Process_unquote(t *testing.T) {
+	var target struct {
+		Name string `env:"NAME,unquote"`
+		Raw  string `env:"RAW"`
+		Key  string `env:"KEY,unquote,unescape"`
+	}
+
+	pr := New(&target)
+	pr.FromArgs([]string{`NAME="my app"`, `RAW="my app"`, `KEY='a\nb'`})
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if target.Name != "my app" || target.Raw != `"my app"` || target.Key != "a\nb" {
+		t.Errorf("Process() target = %+v", target)
+	}
+}
+
+func TestPrimordius_Process_caseCoercion(t *testing.T) {
+	type nested struct {
+		Zone string `yaml:"zone" env:"ZONE,lower"`
+	}
+	var target struct {
+		Region  string `yaml:"region" env:"REGION,upper"`
+		Country string `yaml:"country" env:"COUNTRY,upper"`
+		Name    string `yaml:"name"`
+		Nested  nested `yaml:"nested"`
+	}
+
+	pr := New(&target)
+	pr.FromYAML([]byte("region: eu-west\nname: MixedCase\nnested:\n  zone: A1"))
+	pr.FromArgs([]string{"COUNTRY=de"})
+	if err := pr.Process(); err != nil {
+		t.Fatalf("Process() error = %v", err)
+	}
+
+	if target.Region != "EU-WEST" || target.Country != "DE" || target.Name != "MixedCase" || target.Nested.Zone != "a1" {
+		t.Errorf("Process() target = %+v", target)
+	}
+}
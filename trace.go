@@ -0,0 +1,325 @@
+package primordius
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const secretTagName = "secret"
+
+type (
+	// fieldSnapshot is the copied value of a single field at a point in time.
+	fieldSnapshot struct {
+		value  any
+		secret bool
+	}
+	// snapshot maps dotted field paths to the copied values of the fields.
+	snapshot map[string]fieldSnapshot
+	// trace records which fields were changed by which source during Process.
+	trace struct {
+		// labels holds the label of each processed source by index.
+		labels []string
+		// origins maps a field path to the index of the source which changed it last.
+		origins map[string]int
+		// touched maps a source index to the paths of the fields it changed.
+		touched map[int][]string
+	}
+)
+
+func newTrace() *trace {
+	return &trace{
+		origins: make(map[string]int),
+		touched: make(map[int][]string),
+	}
+}
+
+// record attributes all fields differing between before and after to the source
+// registered at index i.
+func (tr *trace) record(i int, s Source, before, after snapshot) {
+	tr.labels = append(tr.labels, sourceLabel(s))
+	for _, path := range after.changed(before) {
+		tr.origins[path] = i
+		tr.touched[i] = append(tr.touched[i], path)
+	}
+}
+
+// restrict drops the origins of all fields which are not named by or nested in
+// one of the given paths.
+func (tr *trace) restrict(paths []string) {
+	for origin := range tr.origins {
+		keep := false
+		for _, path := range paths {
+			if origin == path || strings.HasPrefix(origin, path+".") {
+				keep = true
+				break
+			}
+		}
+		if !keep {
+			delete(tr.origins, origin)
+		}
+	}
+}
+
+// origin returns the label of the source which set the field at path last, or
+// "default" if no source changed it.
+func (tr *trace) origin(path string) string {
+	i, ok := tr.origins[path]
+	if !ok {
+		return "default"
+	}
+	return tr.source(i)
+}
+
+// source describes the source processed at index i, e.g. "#1 envSource".
+func (tr *trace) source(i int) string {
+	return fmt.Sprintf("#%d %s", i, tr.labels[i])
+}
+
+// sourceCount returns the number of sources which changed the field at path or,
+// for structs, any of its nested fields.
+func (tr *trace) sourceCount(path string) int {
+	n := 0
+	for _, paths := range tr.touched {
+		for _, p := range paths {
+			if p == path || strings.HasPrefix(p, path+".") {
+				n++
+				break
+			}
+		}
+	}
+	return n
+}
+
+// sourceLabel returns a human-readable label for s, preferring its String method.
+func sourceLabel(s Source) string {
+	if st, ok := s.(fmt.Stringer); ok {
+		return st.String()
+	}
+	return strings.TrimPrefix(reflect.TypeOf(s).String(), "*primordius.")
+}
+
+// describer is implemented by sources able to name what they read from.
+type describer interface {
+	// describe returns what the source reads from, e.g. a file name or URL.
+	describe() string
+}
+
+// sourceDescription returns the label of s followed by what it reads from, if known,
+// e.g. "yamlFileSource config.yaml", for error messages.
+func sourceDescription(s Source) string {
+	label := sourceLabel(s)
+	if d, ok := s.(describer); ok {
+		if desc := d.describe(); desc != "" {
+			return label + " " + desc
+		}
+	}
+	return label
+}
+
+// redactURL returns rawURL with a password it contains replaced. The password is
+// also replaced if rawURL cannot be parsed, e.g. because of an invalid escape.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err == nil {
+		return u.Redacted()
+	}
+
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return rawURL
+	}
+	end := strings.IndexAny(rest, "/?#")
+	if end < 0 {
+		end = len(rest)
+	}
+	at := strings.LastIndex(rest[:end], "@")
+	if at < 0 {
+		return rawURL
+	}
+	user, _, ok := strings.Cut(rest[:at], ":")
+	if !ok {
+		return rawURL
+	}
+	return scheme + "://" + user + ":xxxxx" + rest[at:]
+}
+
+// changed returns the sorted paths of all fields whose values differ between ss and other.
+func (ss snapshot) changed(other snapshot) []string {
+	paths := make([]string, 0)
+	for path, fs := range ss {
+		if ofs, ok := other[path]; !ok || !reflect.DeepEqual(fs.value, ofs.value) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// paths returns the sorted field paths of ss.
+func (ss snapshot) paths() []string {
+	paths := make([]string, 0, len(ss))
+	for path := range ss {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// takeSnapshot copies the values of all exported fields of the struct target
+// points to. Nested structs are flattened into dotted paths; structs without
+// exported fields, such as time.Time, are treated as single values. A pointer cycle
+// results in an error wrapping ErrPointerCycle.
+func takeSnapshot(target any) (snapshot, error) {
+	ss := make(snapshot)
+	v := reflect.ValueOf(target)
+	if v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().Kind() == reflect.Struct {
+		if err := ss.add(v.Elem(), "", false, make(map[visit]bool)); err != nil {
+			return nil, err
+		}
+	}
+	return ss, nil
+}
+
+func (ss snapshot) add(s reflect.Value, path string, secret bool, visited map[visit]bool) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		fieldSecret := secret || sf.Tag.Get(secretTagName) == "true"
+
+		f := s.Field(i)
+		if f.Kind() == reflect.Pointer && !f.IsNil() && hasExportedFields(f.Type().Elem()) {
+			v := visit{typ: f.Type(), ptr: f.Pointer()}
+			if visited[v] {
+				return fmt.Errorf("%w: field %s", ErrPointerCycle, fieldPath)
+			}
+			visited[v] = true
+			err := ss.add(f.Elem(), fieldPath, fieldSecret, visited)
+			delete(visited, v)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if hasExportedFields(f.Type()) {
+			if err := ss.add(f, fieldPath, fieldSecret, visited); err != nil {
+				return err
+			}
+			continue
+		}
+		c, err := copyValue(f, fieldPath, visited)
+		if err != nil {
+			return err
+		}
+		ss[fieldPath] = fieldSnapshot{value: c.Interface(), secret: fieldSecret}
+	}
+	return nil
+}
+
+// hasExportedFields reports whether t is a struct type with at least one exported field.
+func hasExportedFields(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if t.Field(i).IsExported() {
+			return true
+		}
+	}
+	return false
+}
+
+// deepCopy returns a copy of v which shares no pointers, slices or maps with v. A
+// pointer cycle results in an error wrapping ErrPointerCycle.
+func deepCopy(v reflect.Value) (reflect.Value, error) {
+	return copyValue(v, "", make(map[visit]bool))
+}
+
+// copyValue copies v, found at path, guarding against cycles of the pointers, slices
+// and maps in visited.
+func copyValue(v reflect.Value, path string, visited map[visit]bool) (reflect.Value, error) {
+	c := reflect.New(v.Type()).Elem()
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Slice, reflect.Map:
+		if v.IsNil() {
+			return c, nil
+		}
+		if v.Kind() != reflect.Slice || v.Len() > 0 {
+			vi := visit{typ: v.Type(), ptr: v.Pointer()}
+			if visited[vi] {
+				return reflect.Value{}, fmt.Errorf("%w: field %s", ErrPointerCycle, path)
+			}
+			visited[vi] = true
+			defer delete(visited, vi)
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		e, err := copyValue(v.Elem(), path, visited)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		p := reflect.New(v.Type().Elem())
+		p.Elem().Set(e)
+		c.Set(p)
+	case reflect.Interface:
+		if !v.IsNil() {
+			e, err := copyValue(v.Elem(), path, visited)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.Set(e)
+		}
+	case reflect.Slice:
+		c.Set(reflect.MakeSlice(v.Type(), v.Len(), v.Len()))
+		fallthrough
+	case reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			e, err := copyValue(v.Index(i), fmt.Sprintf("%s[%d]", path, i), visited)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.Index(i).Set(e)
+		}
+	case reflect.Struct:
+		c.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			sf := v.Type().Field(i)
+			if !sf.IsExported() {
+				continue
+			}
+			fieldPath := sf.Name
+			if path != "" {
+				fieldPath = path + "." + sf.Name
+			}
+			e, err := copyValue(v.Field(i), fieldPath, visited)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.Field(i).Set(e)
+		}
+	case reflect.Map:
+		c.Set(reflect.MakeMapWithSize(v.Type(), v.Len()))
+		iter := v.MapRange()
+		for iter.Next() {
+			e, err := copyValue(iter.Value(), fmt.Sprintf("%s[%v]", path, iter.Key()), visited)
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			c.SetMapIndex(iter.Key(), e)
+		}
+	default:
+		c.Set(v)
+	}
+	return c, nil
+}
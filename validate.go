@@ -0,0 +1,305 @@
+package primordius
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const validateTagName = "validate"
+
+type (
+	// ValidationError reports a field whose value violates a rule declared in its validate tag.
+	ValidationError struct {
+		// Field is the dotted path of the offending field, e.g. "Servers[1].Port".
+		Field string
+		// Rule is the violated rule as written in the tag, e.g. "max=3".
+		Rule string
+		// Reason describes the violation.
+		Reason string
+	}
+	// validatorFunc checks whether v satisfies a rule with the given parameter.
+	// It returns a non-empty reason if it does not.
+	validatorFunc func(v reflect.Value, param string) (string, error)
+)
+
+var validators = map[string]validatorFunc{
+	"min":    validateMin,
+	"max":    validateMax,
+	"gt":     validateComparison(func(c int) bool { return c > 0 }, "greater than"),
+	"gte":    validateComparison(func(c int) bool { return c >= 0 }, "greater than or equal to"),
+	"lt":     validateComparison(func(c int) bool { return c < 0 }, "less than"),
+	"lte":    validateComparison(func(c int) bool { return c <= 0 }, "less than or equal to"),
+	"minlen": validateLength(func(l, bound int) bool { return l >= bound }, "at least"),
+	"maxlen": validateLength(func(l, bound int) bool { return l <= bound }, "at most"),
+	"file":   validatePath(false),
+	"dir":    validatePath(true),
+}
+
+// validatable is implemented by targets validating themselves.
+type validatable interface {
+	Validate() error
+}
+
+// SetValidator registers fn to validate the target after all sources were processed
+// successfully; an error returned by fn is returned by Process. Before fn, required
+// fields, minsources tags and the rules declared in validate tags are checked and, if
+// the target implements a Validate() error method, that method is called. fn receives
+// the target, or the copy being processed by ProcessFields and Reload. Supply nil to
+// remove the validator.
+func (pr *Primordius) SetValidator(fn func(target any) error) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.validator = fn
+}
+
+// validate checks target for missing required fields, fields changed by too few sources
+// as well as against the rules declared in validate tags, its Validate method and the
+// validator registered with pr, in this order.
+func (pr *Primordius) validate(target any) error {
+	if err := checkRequired(target); err != nil {
+		return err
+	}
+	if err := pr.checkMinSources(target); err != nil {
+		return err
+	}
+	if err := validate(target); err != nil {
+		return err
+	}
+	if v, ok := target.(validatable); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	if pr.validator != nil {
+		return pr.validator(target)
+	}
+
+	return nil
+}
+
+func (ve *ValidationError) Error() string {
+	return fmt.Sprintf("validation of field %s failed (%s): %s", ve.Field, ve.Rule, ve.Reason)
+}
+
+// validate checks all fields of the struct target points to against the rules
+// declared in their validate tags, descending into nested structs and slices of structs.
+func validate(target any) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	return validateStruct(v.Elem(), "")
+}
+
+func validateStruct(s reflect.Value, path string) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		f := s.Field(i)
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+
+		if tagVal := sf.Tag.Get(validateTagName); tagVal != "" && tagVal != "-" {
+			if err := validateField(f, fieldPath, tagVal); err != nil {
+				return err
+			}
+		}
+		if err := validateNested(f, fieldPath); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// validateNested descends into v if it is, or contains, structs.
+func validateNested(v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil
+		}
+		return validateNested(v.Elem(), path)
+	case reflect.Struct:
+		return validateStruct(v, path)
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			if err := validateNested(v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateField(f reflect.Value, path, tagVal string) error {
+	for _, rule := range strings.Split(tagVal, ",") {
+		rule = strings.TrimSpace(rule)
+		if rule == "" {
+			continue
+		}
+		name, param, _ := strings.Cut(rule, "=")
+		fn, ok := validators[name]
+		if !ok {
+			return fmt.Errorf("field %s: unknown validation rule %q", path, name)
+		}
+		reason, err := fn(f, param)
+		if err != nil {
+			return fmt.Errorf("field %s: rule %q: %w", path, rule, err)
+		}
+		if reason != "" {
+			return &ValidationError{Field: path, Rule: rule, Reason: reason}
+		}
+	}
+
+	return nil
+}
+
+// validateMin checks the number of elements of collections and the value of numbers.
+func validateMin(v reflect.Value, param string) (string, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		min, err := strconv.Atoi(param)
+		if err != nil {
+			return "", err
+		}
+		if v.Len() < min {
+			return fmt.Sprintf("has %d elements, at least %d required", v.Len(), min), nil
+		}
+		return "", nil
+	}
+
+	return validateComparison(func(c int) bool { return c >= 0 }, "at least")(v, param)
+}
+
+// validateMax checks the number of elements of collections and the value of numbers.
+func validateMax(v reflect.Value, param string) (string, error) {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		max, err := strconv.Atoi(param)
+		if err != nil {
+			return "", err
+		}
+		if v.Len() > max {
+			return fmt.Sprintf("has %d elements, at most %d allowed", v.Len(), max), nil
+		}
+		return "", nil
+	}
+
+	return validateComparison(func(c int) bool { return c <= 0 }, "at most")(v, param)
+}
+
+// validateComparison returns a validatorFunc for numbers which is satisfied if ok
+// holds for the result of comparing the value against the rule parameter.
+func validateComparison(ok func(c int) bool, desc string) validatorFunc {
+	return func(v reflect.Value, param string) (string, error) {
+		c, err := compareNumber(v, param)
+		if err != nil {
+			return "", err
+		}
+		if !ok(c) {
+			return fmt.Sprintf("value %v must be %s %s", v.Interface(), desc, param), nil
+		}
+		return "", nil
+	}
+}
+
+// validateLength returns a validatorFunc for slices, arrays, maps and strings which is
+// satisfied if ok holds for their length and the rule parameter. The length of strings
+// is counted in characters.
+func validateLength(ok func(l, bound int) bool, desc string) validatorFunc {
+	return func(v reflect.Value, param string) (string, error) {
+		bound, err := strconv.Atoi(param)
+		if err != nil {
+			return "", err
+		}
+		var l int
+		switch v.Kind() {
+		case reflect.Slice, reflect.Array, reflect.Map:
+			l = v.Len()
+		case reflect.String:
+			l = utf8.RuneCountInString(v.String())
+		default:
+			return "", fmt.Errorf("not applicable to kind %s", v.Kind())
+		}
+		if !ok(l, bound) {
+			return fmt.Sprintf("has length %d, must be %s %d", l, desc, bound), nil
+		}
+		return "", nil
+	}
+}
+
+// validatePath returns a validatorFunc for strings which is satisfied if the value
+// names an existing directory (dir is true) or an existing file other than a directory.
+func validatePath(dir bool) validatorFunc {
+	return func(v reflect.Value, _ string) (string, error) {
+		if v.Kind() != reflect.String {
+			return "", fmt.Errorf("not applicable to kind %s", v.Kind())
+		}
+		fi, err := os.Stat(v.String())
+		switch {
+		case errors.Is(err, fs.ErrNotExist):
+			return fmt.Sprintf("%q does not exist", v.String()), nil
+		case err != nil:
+			return "", err
+		case dir && !fi.IsDir():
+			return fmt.Sprintf("%q is not a directory", v.String()), nil
+		case !dir && fi.IsDir():
+			return fmt.Sprintf("%q is a directory, not a file", v.String()), nil
+		}
+		return "", nil
+	}
+}
+
+// compareNumber compares the numeric value v with param, parsed according to the
+// kind of v. It returns -1, 0 or +1 if v is less than, equal to or greater than param.
+func compareNumber(v reflect.Value, param string) (int, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		p, err := strconv.ParseInt(param, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compare(v.Int() > p, v.Int() < p), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if p, err := strconv.ParseInt(param, 10, 64); err == nil && p < 0 {
+			return 1, nil
+		}
+		p, err := strconv.ParseUint(param, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compare(v.Uint() > p, v.Uint() < p), nil
+	case reflect.Float32, reflect.Float64:
+		p, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return 0, err
+		}
+		return compare(v.Float() > p, v.Float() < p), nil
+	}
+
+	return 0, fmt.Errorf("not applicable to kind %s", v.Kind())
+}
+
+func compare(greater, less bool) int {
+	switch {
+	case greater:
+		return 1
+	case less:
+		return -1
+	}
+	return 0
+}
@@ -0,0 +1,192 @@
+package primordius
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func Test_validate(t *testing.T) {
+	type server struct {
+		Host  string `toml:"host"`
+		Ports []int  `toml:"ports" validate:"max=2"`
+	}
+	type target struct {
+		Servers []server `toml:"servers" validate:"max=2"`
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		wantField string
+	}{
+		{"within bounds", "[[servers]]\nhost = \"a\"\n[[servers]]\nhost = \"b\"", ""},
+		{"too many tables", "[[servers]]\nhost = \"a\"\n[[servers]]\nhost = \"b\"\n[[servers]]\nhost = \"c\"", "Servers"},
+		{"nested slice too long", "[[servers]]\nhost = \"a\"\nports = [1, 2, 3]", "Servers[0].Ports"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			pr := New(&got)
+			pr.FromTOML([]byte(tc.content))
+			err := pr.Process()
+
+			var ve *ValidationError
+			if tc.wantField == "" {
+				if err != nil {
+					t.Fatalf("Process() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.As(err, &ve) {
+				t.Fatalf("Process() error = %v, want *ValidationError", err)
+			}
+			if ve.Field != tc.wantField {
+				t.Errorf("ValidationError.Field = %q, want %q", ve.Field, tc.wantField)
+			}
+		})
+	}
+}
+
+func Test_validateField_comparisons(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   any
+		rules   string
+		wantErr bool
+	}{
+		{"int within gt/lte", 5, "gt=0,lte=100", false},
+		{"int at exclusive lower bound", 0, "gt=0,lte=100", true},
+		{"int at inclusive upper bound", 100, "gt=0,lte=100", false},
+		{"int above upper bound", 101, "gt=0,lte=100", true},
+		{"uint against negative bound", uint(0), "gt=-1", false},
+		{"float at exclusive upper bound", 1.0, "gte=0,lt=1", true},
+		{"float within bounds", 0.5, "gte=0,lt=1", false},
+		{"int min/max", int8(-3), "min=-3,max=3", false},
+		{"int below min", int8(-4), "min=-3,max=3", true},
+		{"string not comparable", "abc", "gt=1", true},
+		{"unknown rule", 1, "between=1", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateField(reflect.ValueOf(tc.value), "F", tc.rules)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateField() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateField_lengths(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    any
+		rules    string
+		wantRule string
+		wantErr  bool
+	}{
+		{"slice within bounds", []string{"a"}, "minlen=1,maxlen=2", "", false},
+		{"empty slice", []string{}, "minlen=1,maxlen=2", "minlen=1", true},
+		{"map too long", map[string]int{"a": 1, "b": 2, "c": 3}, "minlen=1,maxlen=2", "maxlen=2", true},
+		{"array", [2]int{}, "maxlen=2", "", false},
+		{"string counted in characters", "äöü", "maxlen=3", "", false},
+		{"string too short", "ab", "minlen=3", "minlen=3", true},
+		{"not applicable", 5, "minlen=1", "", true},
+		{"invalid bound", "abc", "maxlen=x", "", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateField(reflect.ValueOf(tc.value), "F", tc.rules)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("validateField() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			var ve *ValidationError
+			if errors.As(err, &ve) && ve.Rule != tc.wantRule {
+				t.Errorf("ValidationError.Rule = %q, want %q", ve.Rule, tc.wantRule)
+			}
+		})
+	}
+}
+
+func Test_validatePath(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "cert.pem")
+	if err := os.WriteFile(file, []byte("cert"), 0600); err != nil {
+		t.Fatalf("failed to write test file: %s", err.Error())
+	}
+
+	tests := []struct {
+		name    string
+		value   string
+		rules   string
+		wantErr bool
+	}{
+		{"existing file", file, "file", false},
+		{"missing file", filepath.Join(dir, "missing.pem"), "file", true},
+		{"directory as file", dir, "file", true},
+		{"existing directory", dir, "dir", false},
+		{"file as directory", file, "dir", true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateField(reflect.ValueOf(tc.value), "F", tc.rules)
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validateField() error = %v, wantErr %v", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+// testSelfValidating rejects a zero port in its Validate method.
+type testSelfValidating struct {
+	Port int    `json:"port"`
+	Host string `json:"host"`
+}
+
+func (s *testSelfValidating) Validate() error {
+	if s.Port == 0 {
+		return errors.New("port is required")
+	}
+	return nil
+}
+
+func TestPrimordius_SetValidator(t *testing.T) {
+	errNoHost := errors.New("host is required")
+	validator := func(target any) error {
+		if target.(*testSelfValidating).Host == "" {
+			return errNoHost
+		}
+		return nil
+	}
+
+	tests := []struct {
+		name      string
+		content   string
+		validator func(target any) error
+		wantErr   string
+	}{
+		{"valid", `{"port": 80, "host": "localhost"}`, validator, ""},
+		{"Validate method", `{"host": "localhost"}`, validator, "port is required"},
+		{"validator", `{"port": 80}`, validator, errNoHost.Error()},
+		{"without validator", `{"port": 80}`, nil, ""},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var target testSelfValidating
+			pr := New(&target)
+			pr.SetValidator(tc.validator)
+			pr.FromJSON([]byte(tc.content))
+			err := pr.Process()
+			if (err == nil) != (tc.wantErr == "") || err != nil && err.Error() != tc.wantErr {
+				t.Errorf("Process() error = %v, want %q", err, tc.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package primordius
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+const vaultTagName = "vault"
+
+type (
+	// VaultReader is the subset of a Vault client required by the Vault source. Wrap
+	// your client to satisfy it, e.g. for github.com/hashicorp/vault/api:
+	//
+	//	func (a adapter) Read(ctx context.Context, path string) (map[string]any, error) {
+	//		s, err := a.c.Logical().ReadWithContext(ctx, path)
+	//		if err != nil || s == nil {
+	//			return nil, err
+	//		}
+	//		return s.Data, nil
+	//	}
+	VaultReader interface {
+		// Read returns the data of the secret at path, or nil if there is none.
+		Read(ctx context.Context, path string) (map[string]any, error)
+	}
+	vaultSource struct {
+		client VaultReader
+		path   string
+	}
+)
+
+func (vs *vaultSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	data, err := vs.client.Read(context.Background(), vs.path)
+	if err != nil {
+		return fmt.Errorf("reading Vault secret %s: %w", vs.path, err)
+	}
+	if data == nil {
+		return fmt.Errorf("reading Vault secret %s: no secret found", vs.path)
+	}
+	// secrets of KV version 2 mounts hold the actual data below "data"
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+
+	return vs.apply(v.Elem(), "", data)
+}
+
+func (vs *vaultSource) describe() string {
+	return vs.path
+}
+
+// apply assigns the values of data to the fields of the struct s at path whose vault
+// tag, or else env tag, names their key, descending into nested structs. Errors never
+// contain the values.
+func (vs *vaultSource) apply(s reflect.Value, path string, data map[string]any) error {
+	t := s.Type()
+	for i := 0; i < s.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		fieldPath := sf.Name
+		if path != "" {
+			fieldPath = path + "." + sf.Name
+		}
+		f := s.Field(i)
+
+		key, _ := parseTag(sf.Tag.Get(vaultTagName))
+		if key == "" {
+			key, _ = parseTag(sf.Tag.Get(tagName))
+		}
+		if key == "-" {
+			continue
+		}
+		if key == "" {
+			if f.Kind() == reflect.Pointer && !f.IsNil() {
+				f = f.Elem()
+			}
+			if hasExportedFields(f.Type()) {
+				if err := vs.apply(f, fieldPath, data); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		raw, ok := data[key]
+		if !ok || raw == nil {
+			continue
+		}
+		val, ok := raw.(string)
+		if !ok {
+			b, err := json.Marshal(raw)
+			if err != nil {
+				return fmt.Errorf("field %s: unsupported value of Vault key %s", fieldPath, key)
+			}
+			val = string(b)
+		}
+		if err := setValue(f, val); err != nil {
+			return fmt.Errorf("field %s: value of Vault key %s cannot be converted to %s", fieldPath, key, f.Type())
+		}
+	}
+
+	return nil
+}
+
+// FromVault adds a Source to pr which reads the secret at path from Vault, e.g.
+// "secret/data/my-app", and assigns its values to the fields whose vault tag, or else
+// env tag, names their key. The data of secrets of KV version 2 mounts is unwrapped.
+// An error reading the secret, including a missing secret, fails Process. Values are
+// never part of error messages.
+func (pr *Primordius) FromVault(client VaultReader, path string) {
+	pr.AddSource(&vaultSource{client: client, path: path})
+}
@@ -0,0 +1,64 @@
+package primordius
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+type fakeVault map[string]map[string]any
+
+func (fv fakeVault) Read(_ context.Context, path string) (map[string]any, error) {
+	if path == "secret/forbidden" {
+		return nil, errors.New("vault: permission denied")
+	}
+	return fv[path], nil
+}
+
+func Test_vaultSource_ToTarget(t *testing.T) {
+	type database struct {
+		Password string `vault:"db_password" env:"DB_PASSWORD"`
+		Port     int    `vault:"db_port"`
+	}
+	type target struct {
+		APIKey   string `env:"api_key"`
+		Ignored  string `vault:"-" env:"api_key"`
+		Database database
+	}
+
+	vault := fakeVault{
+		"secret/app":      {"api_key": "k3y", "db_password": "hunter2", "db_port": 5432.0},
+		"secret/data/app": {"data": map[string]any{"api_key": "k3y"}, "metadata": map[string]any{"version": 3}},
+		"secret/invalid":  {"db_port": "hunter2"},
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		want    target
+		wantErr bool
+	}{
+		{"kv v1", "secret/app", target{APIKey: "k3y", Database: database{Password: "hunter2", Port: 5432}}, false},
+		{"kv v2", "secret/data/app", target{APIKey: "k3y"}, false},
+		{"missing secret", "secret/missing", target{}, true},
+		{"read error", "secret/forbidden", target{}, true},
+		{"invalid value", "secret/invalid", target{}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := (&vaultSource{client: vault, path: tc.path}).ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "hunter2") {
+				t.Errorf("ToTarget() error = %v, leaks secret value", err)
+			}
+			if got != tc.want {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package primordius
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"time"
+)
+
+const (
+	// DefaultWatchInterval is the default interval in which watched files are checked for changes.
+	DefaultWatchInterval = time.Second
+	// DefaultWatchDebounce is the default period without further changes to wait for
+	// before reloading.
+	DefaultWatchDebounce = 500 * time.Millisecond
+)
+
+// watchedSource is implemented by sources reading from files, or other resources with a
+// notion of modification, which can be watched for changes.
+type watchedSource interface {
+	Source
+	// modTime returns the modification time of the file. It returns the zero time
+	// if the file cannot be accessed, so removing and recreating it counts as a change.
+	modTime() time.Time
+}
+
+func fileModTime(name string) time.Time {
+	fi, err := os.Stat(name)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+func (y *yamlFileSource) modTime() time.Time  { return fileModTime(y.name) }
+func (j *jsonFileSource) modTime() time.Time  { return fileModTime(j.name) }
+func (to *tomlFileSource) modTime() time.Time { return fileModTime(to.name) }
+func (fls *fileSource) modTime() time.Time    { return fileModTime(fls.name) }
+func (x *xmlFileSource) modTime() time.Time   { return fileModTime(x.name) }
+func (fss *fsSource) modTime() time.Time {
+	fi, err := fs.Stat(fss.fsys, fss.name)
+	if err != nil {
+		return time.Time{}
+	}
+	return fi.ModTime()
+}
+
+// SetWatchInterval sets the interval in which WatchContext checks watched files for
+// changes and the period without further changes it waits for before reloading.
+// Non-positive values select DefaultWatchInterval and DefaultWatchDebounce, respectively.
+func (pr *Primordius) SetWatchInterval(interval, debounce time.Duration) {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	pr.watchInterval = interval
+	pr.watchDebounce = debounce
+}
+
+// WatchContext watches the files read by the registered file sources for changes and
+// calls Process whenever one of them changed, passing the result to onChange.
+// A burst of changes, e.g. an editor writing a file in several steps, is coalesced into
+// a single reload which happens once the files did not change for the debounce period
+// set by SetWatchInterval. Files are polled, so watching works on every platform.
+// HTTP sources are polled using conditional requests. Sources which are not file-backed,
+// such as env or readers, are ignored. WatchContext returns immediately; watching
+// stops when ctx is done.
+func (pr *Primordius) WatchContext(ctx context.Context, onChange func(error)) {
+	last := pr.modTimes()
+	go pr.watch(ctx, last, onChange)
+}
+
+// Watch works like WatchContext, but blocks until ctx is done.
+func (pr *Primordius) Watch(ctx context.Context, onReload func(error)) {
+	pr.watch(ctx, pr.modTimes(), onReload)
+}
+
+// watch polls the modification times of the watched files, starting from last, and
+// reprocesses once they stopped changing, until ctx is done.
+func (pr *Primordius) watch(ctx context.Context, last []time.Time, onChange func(error)) {
+	pr.mu.RLock()
+	interval, debounce := pr.watchInterval, pr.watchDebounce
+	pr.mu.RUnlock()
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+	if debounce <= 0 {
+		debounce = DefaultWatchDebounce
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var (
+		pending   bool
+		changedAt time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			if current := pr.modTimes(); !sameTimes(current, last) {
+				last, pending, changedAt = current, true, now
+				continue
+			}
+			if pending && now.Sub(changedAt) >= debounce {
+				pending = false
+				onChange(pr.Process())
+			}
+		}
+	}
+}
+
+// modTimes returns the modification times of the files of all watched sources by
+// source index. The entries of other sources are zero.
+func (pr *Primordius) modTimes() []time.Time {
+	// modTime may update the state of a source, e.g. the cached response of an HTTP source
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+	times := make([]time.Time, len(pr.sources))
+	for i, s := range pr.sources {
+		if ws, ok := s.(watchedSource); ok {
+			times[i] = ws.modTime()
+		}
+	}
+	return times
+}
+
+func sameTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,132 @@
+package primordius
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestPrimordius_WatchContext(t *testing.T) {
+	var target struct {
+		Port int `json:"port"`
+	}
+	name := filepath.Join(t.TempDir(), "config.json")
+	write := func(content string, mtime time.Time) {
+		if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	base := time.Now().Add(-time.Hour)
+	write(`{"port": 80}`, base)
+
+	pr := New(&target)
+	pr.FromJSONFile(name)
+	if err := pr.Process(); err != nil {
+		t.Fatal(err)
+	}
+	pr.SetWatchInterval(5*time.Millisecond, 50*time.Millisecond)
+
+	reloads := make(chan error, 10)
+	ctx, cancel := context.WithCancel(context.Background())
+	pr.WatchContext(ctx, func(err error) { reloads <- err })
+
+	// a burst of writes results in a single reload
+	for i := 1; i <= 3; i++ {
+		write(`{"port": 8080}`, base.Add(time.Duration(i)*time.Second))
+		time.Sleep(10 * time.Millisecond)
+	}
+	select {
+	case err := <-reloads:
+		if err != nil {
+			t.Fatalf("reload error = %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no reload after change")
+	}
+	if target.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", target.Port)
+	}
+	select {
+	case <-reloads:
+		t.Error("got more than one reload for a burst of changes")
+	case <-time.After(150 * time.Millisecond):
+	}
+
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	write(`{"port": 9090}`, base.Add(time.Minute))
+	select {
+	case <-reloads:
+		t.Error("got reload after context was canceled")
+	case <-time.After(150 * time.Millisecond):
+	}
+}
+
+func TestPrimordius_Watch(t *testing.T) {
+	var target struct {
+		Port int `yaml:"port"`
+	}
+	name := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(name, []byte("port: 80"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	base := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(name, base, base); err != nil {
+		t.Fatal(err)
+	}
+
+	pr := New(&target)
+	pr.FromYAMLFile(name)
+	pr.FromEnv("WATCH_")
+	if err := pr.Process(); err != nil {
+		t.Fatal(err)
+	}
+	pr.SetWatchInterval(5*time.Millisecond, 10*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	reloaded := make(chan error, 1)
+	go func() {
+		pr.Watch(ctx, func(err error) {
+			reloaded <- err
+			cancel()
+		})
+		close(done)
+	}()
+
+	if err := os.WriteFile(name, []byte("port: 8080"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	// Watch may not have taken the initial modification times yet, so keep changing them
+	timeout := time.After(2 * time.Second)
+	for i := 1; ; i++ {
+		mtime := base.Add(time.Duration(i) * time.Second)
+		if err := os.Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case err := <-reloaded:
+			if err != nil {
+				t.Fatalf("reload error = %v", err)
+			}
+		case <-time.After(50 * time.Millisecond):
+			continue
+		case <-timeout:
+			t.Fatal("no reload after change")
+		}
+		break
+	}
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not return after context was canceled")
+	}
+	if target.Port != 8080 {
+		t.Errorf("Port = %d, want 8080", target.Port)
+	}
+}
@@ -0,0 +1,71 @@
+package primordius
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+)
+
+const FormatXML Format = "xml"
+
+type (
+	xmlFileSource struct {
+		name string
+	}
+	xmlContentSource struct {
+		content []byte
+	}
+	xmlReaderSource struct {
+		r io.Reader
+	}
+)
+
+func init() {
+	RegisterCodec(FormatXML, xml.Unmarshal)
+}
+
+func (x *xmlFileSource) ToTarget(t any) error {
+	return decodeSource(x, t)
+}
+
+func (x *xmlFileSource) describe() string {
+	return x.name
+}
+
+func (x *xmlFileSource) load() ([]byte, Format, error) {
+	cont, err := os.ReadFile(x.name)
+	return cont, FormatXML, err
+}
+
+func (x *xmlContentSource) ToTarget(t any) error {
+	return decodeSource(x, t)
+}
+
+func (x *xmlContentSource) load() ([]byte, Format, error) {
+	return x.content, FormatXML, nil
+}
+
+func (x *xmlReaderSource) ToTarget(t any) error {
+	return decodeSource(x, t)
+}
+
+func (x *xmlReaderSource) load() ([]byte, Format, error) {
+	cont, err := io.ReadAll(x.r)
+	return cont, FormatXML, err
+}
+
+// FromXMLFile adds a Source to pr which reads values from an XML file. The name of
+// the root element is not checked unless the target has an XMLName field.
+func (pr *Primordius) FromXMLFile(name string) {
+	pr.AddSource(&xmlFileSource{name: name})
+}
+
+// FromXML adds a Source to pr which reads values from an XML block.
+func (pr *Primordius) FromXML(content []byte) {
+	pr.AddSource(&xmlContentSource{content: content})
+}
+
+// FromXMLReader adds a Source to pr which reads XML content from r.
+func (pr *Primordius) FromXMLReader(r io.Reader) {
+	pr.AddSource(&xmlReaderSource{r: r})
+}
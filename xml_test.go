@@ -0,0 +1,67 @@
+package primordius
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func Test_xmlSources(t *testing.T) {
+	type server struct {
+		Host string `xml:"host,attr"`
+		Port int    `xml:"port"`
+	}
+	type target struct {
+		Name    string   `xml:"name"`
+		Servers []server `xml:"servers>server"`
+	}
+	content := `<?xml version="1.0"?>
+<config>
+	<name>app</name>
+	<servers>
+		<server host="a.local"><port>80</port></server>
+		<server host="b.local"><port>81</port></server>
+	</servers>
+</config>`
+	want := target{Name: "app", Servers: []server{{"a.local", 80}, {"b.local", 81}}}
+	name := filepath.Join(t.TempDir(), "config.xml")
+	if err := os.WriteFile(name, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		source  Source
+		wantErr bool
+	}{
+		{"file", &xmlFileSource{name: name}, false},
+		{"content", &xmlContentSource{content: []byte(content)}, false},
+		{"reader", &xmlReaderSource{r: strings.NewReader(content)}, false},
+		{"missing file", &xmlFileSource{name: name + ".missing"}, true},
+		{"malformed", &xmlContentSource{content: []byte("<config><name>app</config>")}, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			var got target
+			err := tc.source.ToTarget(&got)
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("ToTarget() error = %v, wantErr %v", err, tc.wantErr)
+			}
+			if !tc.wantErr && !reflect.DeepEqual(got, want) {
+				t.Errorf("ToTarget() got = %+v, want %+v", got, want)
+			}
+		})
+	}
+
+	var got target
+	pr := New(&got)
+	pr.FromXMLFile(name + ".missing")
+	if err := pr.Process(); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Process() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
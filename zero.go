@@ -0,0 +1,81 @@
+package primordius
+
+import "reflect"
+
+type (
+	// zeroer is implemented by types with a semantic zero value differing from
+	// their Go zero value, e.g. an optional wrapper which is set to zero.
+	zeroer interface {
+		IsZero() bool
+	}
+	noOverwriteSource struct {
+		source Source
+	}
+)
+
+var zeroerType = reflect.TypeOf((*zeroer)(nil)).Elem()
+
+// isZero reports whether v is zero, preferring an IsZero method of its type over
+// reflect.Value.IsZero.
+func isZero(v reflect.Value) bool {
+	if v.Kind() == reflect.Pointer && v.IsNil() {
+		return true
+	}
+	if v.Type().Implements(zeroerType) && v.CanInterface() {
+		return v.Interface().(zeroer).IsZero()
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(zeroerType) && v.Addr().CanInterface() {
+		return v.Addr().Interface().(zeroer).IsZero()
+	}
+	return v.IsZero()
+}
+
+func (ns *noOverwriteSource) ToTarget(t any) error {
+	v := reflect.ValueOf(t)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return ErrInvalidSpecification
+	}
+
+	c, err := deepCopy(v)
+	if err != nil {
+		return err
+	}
+	if err := ns.source.ToTarget(c.Interface()); err != nil {
+		return err
+	}
+	fillZero(v.Elem(), c.Elem())
+
+	return nil
+}
+
+func (ns *noOverwriteSource) describe() string {
+	return sourceDescription(ns.source)
+}
+
+// fillZero sets all zero fields of the struct dst to the values of the respective
+// fields of src, descending into nested structs.
+func fillZero(dst, src reflect.Value) {
+	t := dst.Type()
+	for i := 0; i < dst.NumField(); i++ {
+		if !t.Field(i).IsExported() {
+			continue
+		}
+		df, sf := dst.Field(i), src.Field(i)
+		ft := t.Field(i).Type
+		if hasExportedFields(ft) && !ft.Implements(zeroerType) && !reflect.PointerTo(ft).Implements(zeroerType) {
+			fillZero(df, sf)
+			continue
+		}
+		if isZero(df) {
+			df.Set(sf)
+		}
+	}
+}
+
+// NoOverwrite returns a Source which only writes values into the target by means of
+// s for fields which are zero at processing time, so values set by sources with lower
+// priority are kept. Types can define their own notion of zero by implementing an
+// IsZero() bool method, which is consulted instead of comparing with the Go zero value.
+func NoOverwrite(s Source) Source {
+	return &noOverwriteSource{source: s}
+}
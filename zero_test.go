@@ -0,0 +1,59 @@
+package primordius
+
+import "testing"
+
+// testOptional is zero if it is not set, regardless of its value.
+type testOptional struct {
+	Value int
+	Set   bool
+}
+
+func (o testOptional) IsZero() bool {
+	return !o.Set
+}
+
+type testOptionalPtr struct {
+	Value string
+}
+
+func (o *testOptionalPtr) IsZero() bool {
+	return o.Value == "none"
+}
+
+func TestNoOverwrite(t *testing.T) {
+	type nested struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+	type target struct {
+		Host    string          `json:"host"`
+		Nested  nested          `json:"nested"`
+		Retries testOptional    `json:"retries"`
+		Mode    testOptionalPtr `json:"mode"`
+		Limit   testOptional    `json:"limit"`
+	}
+
+	got := target{
+		Host:    "localhost",
+		Nested:  nested{Port: 80},
+		Retries: testOptional{Value: 0, Set: false},
+		Mode:    testOptionalPtr{Value: "none"},
+		Limit:   testOptional{Value: 0, Set: true},
+	}
+	s := NoOverwrite(&jsonContentSource{content: []byte(`{"host": "example.com", "nested": {"name": "n", "port": 8080},
+		"retries": {"Value": 3, "Set": true}, "mode": {"Value": "fast"}, "limit": {"Value": 9, "Set": true}}`)})
+	if err := s.ToTarget(&got); err != nil {
+		t.Fatalf("ToTarget() error = %v", err)
+	}
+
+	want := target{
+		Host:    "localhost",
+		Nested:  nested{Name: "n", Port: 80},
+		Retries: testOptional{Value: 3, Set: true},
+		Mode:    testOptionalPtr{Value: "fast"},
+		Limit:   testOptional{Value: 0, Set: true},
+	}
+	if got != want {
+		t.Errorf("ToTarget() got = %+v, want %+v", got, want)
+	}
+}